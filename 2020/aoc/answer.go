@@ -0,0 +1,22 @@
+package aoc
+
+import "strconv"
+
+// An Answer is the result of solving one part of a puzzle. Answers are always
+// submitted to the site as text, so that's how we keep them; numeric answers
+// are stored in base 10. Answers can be compared with ==.
+type Answer string
+
+// Int returns the Answer for a numeric result.
+func Int(n int) Answer {
+	return Answer(strconv.Itoa(n))
+}
+
+// Int returns the numeric value of the answer, if it has one.
+func (a Answer) Int() (int, error) {
+	return strconv.Atoi(string(a))
+}
+
+func (a Answer) String() string {
+	return string(a)
+}
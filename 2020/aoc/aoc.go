@@ -0,0 +1,98 @@
+// Package aoc holds the pieces shared by every day's solution. Each day
+// registers itself here from an init function, and the aoc command looks the
+// solutions up by year and day so it can run any of them.
+package aoc
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// A Solver solves the two parts of one day's puzzle. Each part reads the
+// whole puzzle input from input, so the parts can be run independently.
+type Solver interface {
+	Part1(input io.Reader) (Answer, error)
+	Part2(input io.Reader) (Answer, error)
+}
+
+// A Parser is a Solver that can parse its input without solving anything,
+// so the time spent parsing can be measured on its own.
+type Parser interface {
+	Parse(input io.Reader) error
+}
+
+// A BothSolver is a Solver that can solve both parts from one reading of its
+// input, for days whose parts share slow work like parsing. The aoc command
+// uses it when running both parts; Part1 and Part2 must still work on their
+// own, and give the same answers.
+type BothSolver interface {
+	Solve(input io.Reader) (part1, part2 Answer, err error)
+}
+
+// A Flagger is a Solver with options. Flags adds them to the aoc command's
+// flag set, so they should be named to make sense next to other days' flags,
+// and default to solving the puzzle as written. The Solver must be registered
+// as a pointer for the flag values to reach it.
+type Flagger interface {
+	Flags(fs *flag.FlagSet)
+}
+
+// Solutions are keyed by year and day.
+type key struct {
+	year int
+	day  int
+}
+
+var registry = map[key]Solver{}
+
+// Register makes a solver available for the given year and day. It is meant
+// to be called from a day's init function, and panics if that day already has
+// a solver, since that can only be a programming mistake.
+func Register(year, day int, s Solver) {
+	k := key{year, day}
+	if _, dup := registry[k]; dup {
+		panic(fmt.Sprintf("aoc: Register called twice for %d day %d", year, day))
+	}
+	registry[k] = s
+}
+
+// Lookup returns the solver registered for the given year and day.
+func Lookup(year, day int) (Solver, bool) {
+	s, ok := registry[key{year, day}]
+	return s, ok
+}
+
+// Days returns the days that have a registered solver in the given year,
+// in ascending order.
+func Days(year int) []int {
+	var days []int
+	for k := range registry {
+		if k.year == year {
+			days = append(days, k.day)
+		}
+	}
+	sort.Ints(days)
+	return days
+}
+
+// All returns every registered solver, ordered by year and day.
+func All() []Solver {
+	keys := make([]key, 0, len(registry))
+	for k := range registry {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].year != keys[j].year {
+			return keys[i].year < keys[j].year
+		}
+		return keys[i].day < keys[j].day
+	})
+
+	solvers := make([]Solver, len(keys))
+	for i, k := range keys {
+		solvers[i] = registry[k]
+	}
+	return solvers
+}
@@ -0,0 +1,125 @@
+// Package aoctest has helpers for testing solvers against the worked examples
+// in the puzzle text and against our own puzzle inputs.
+package aoctest
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+)
+
+// A Case is one puzzle input and the answers expected for it. An empty
+// answer means the case doesn't check that part, since the puzzle examples
+// don't always cover both.
+type Case struct {
+	Name  string
+	Input string
+	Part1 aoc.Answer
+	Part2 aoc.Answer
+}
+
+// Run checks each case against the solver, as a subtest per case and part.
+// A BothSolver's Solve is checked too.
+func Run(t *testing.T, s aoc.Solver, cases []Case) {
+	t.Helper()
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			if c.Part1 != "" {
+				check(t, "part 1", s.Part1, c.Input, c.Part1)
+			}
+			if c.Part2 != "" {
+				check(t, "part 2", s.Part2, c.Input, c.Part2)
+			}
+			checkBoth(t, s, c.Input, c.Part1, c.Part2)
+		})
+	}
+}
+
+// Input returns the contents of the input.txt in the current directory,
+// which for a test is the day's package directory.
+func Input(tb testing.TB) []byte {
+	tb.Helper()
+
+	dat, err := os.ReadFile("input.txt")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return dat
+}
+
+// Golden checks the solver's answers for the day's input.txt.
+func Golden(t *testing.T, s aoc.Solver, part1, part2 aoc.Answer) {
+	t.Helper()
+
+	dat := Input(t)
+	check(t, "part 1", s.Part1, string(dat), part1)
+	check(t, "part 2", s.Part2, string(dat), part2)
+	checkBoth(t, s, string(dat), part1, part2)
+}
+
+// BenchmarkParse times parsing the day's input.txt.
+func BenchmarkParse(b *testing.B, p aoc.Parser) {
+	dat := Input(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := p.Parse(bytes.NewReader(dat)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkPart times solving one part for the day's input.txt.
+func BenchmarkPart(b *testing.B, part func(io.Reader) (aoc.Answer, error)) {
+	dat := Input(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := part(bytes.NewReader(dat)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// checkBoth checks the answers from a BothSolver's Solve, if s is one. An
+// empty answer isn't checked.
+func checkBoth(t *testing.T, s aoc.Solver, input string, part1, part2 aoc.Answer) {
+	t.Helper()
+
+	both, ok := s.(aoc.BothSolver)
+	if !ok {
+		return
+	}
+	got1, got2, err := both.Solve(strings.NewReader(input))
+	if err != nil {
+		t.Errorf("both parts: %v", err)
+		return
+	}
+	if part1 != "" && got1 != part1 {
+		t.Errorf("both parts: part 1 = %s, want %s", got1, part1)
+	}
+	if part2 != "" && got2 != part2 {
+		t.Errorf("both parts: part 2 = %s, want %s", got2, part2)
+	}
+}
+
+func check(t *testing.T, name string, part func(io.Reader) (aoc.Answer, error), input string, want aoc.Answer) {
+	t.Helper()
+
+	got, err := part(strings.NewReader(input))
+	if err != nil {
+		t.Errorf("%s: %v", name, err)
+		return
+	}
+	if got != want {
+		t.Errorf("%s = %s, want %s", name, got, want)
+	}
+}
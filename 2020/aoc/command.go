@@ -0,0 +1,44 @@
+package aoc
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// A Command is an extra subcommand for the aoc command, for tools that go
+// beyond answering the puzzle, like reports on a day's input. Days register
+// their commands from an init function, next to their solver.
+type Command struct {
+	Name    string // what follows "aoc" on the command line
+	Usage   string // the arguments, for the usage message
+	Summary string // one line saying what it does
+	Run     func(args []string, stdout io.Writer) error
+}
+
+var commands = map[string]Command{}
+
+// RegisterCommand makes a command available to the aoc command. Like
+// Register, it panics if the name is already taken.
+func RegisterCommand(c Command) {
+	if _, dup := commands[c.Name]; dup {
+		panic(fmt.Sprintf("aoc: RegisterCommand called twice for %q", c.Name))
+	}
+	commands[c.Name] = c
+}
+
+// LookupCommand returns the command with the given name.
+func LookupCommand(name string) (Command, bool) {
+	c, ok := commands[name]
+	return c, ok
+}
+
+// Commands returns every registered command, sorted by name.
+func Commands() []Command {
+	all := make([]Command, 0, len(commands))
+	for _, c := range commands {
+		all = append(all, c)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
@@ -0,0 +1,81 @@
+package aoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A ParseError reports a problem with a puzzle input, and where in the input
+// it was found. Solvers fill in the position and offending text; whoever
+// opened the input (usually the aoc command) fills in the year, day and file
+// name, since the solvers only ever see a reader.
+type ParseError struct {
+	Year int    // puzzle year, 0 if unknown
+	Day  int    // puzzle day, 0 if unknown
+	File string // input file name, "" if unknown
+	Line int    // 1-based line number, 0 if unknown
+	Col  int    // 1-based column number, 0 if unknown
+	Text string // the offending line of input
+	Err  error  // what was wrong with it
+}
+
+func (e *ParseError) Error() string {
+	var b strings.Builder
+
+	if e.Day != 0 {
+		fmt.Fprintf(&b, "%d day %02d: ", e.Year, e.Day)
+	}
+
+	file := e.File
+	if file == "" {
+		file = "input"
+	}
+	b.WriteString(file)
+	if e.Line != 0 {
+		fmt.Fprintf(&b, ":%d", e.Line)
+		if e.Col != 0 {
+			fmt.Fprintf(&b, ":%d", e.Col)
+		}
+	}
+
+	fmt.Fprintf(&b, ": %v", e.Err)
+	if e.Text != "" {
+		fmt.Fprintf(&b, ": %q", e.Text)
+	}
+
+	return b.String()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Caret returns the offending text with a second line marking the column the
+// error was found at, for printing under the error message. It returns ""
+// when there's no text or column to show.
+func (e *ParseError) Caret() string {
+	if e.Text == "" || e.Col == 0 {
+		return ""
+	}
+
+	// Keep tabs in the padding so the caret lines up however the terminal
+	// renders them.
+	var pad strings.Builder
+	for i, c := range e.Text {
+		if i >= e.Col-1 {
+			break
+		}
+		if c == '\t' {
+			pad.WriteByte('\t')
+		} else {
+			pad.WriteByte(' ')
+		}
+	}
+
+	return e.Text + "\n" + pad.String() + "^"
+}
+
+// Errorf returns a ParseError for the given 1-based line and column of text.
+func Errorf(line, col int, text string, format string, a ...interface{}) *ParseError {
+	return &ParseError{Line: line, Col: col, Text: text, Err: fmt.Errorf(format, a...)}
+}
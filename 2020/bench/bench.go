@@ -0,0 +1,184 @@
+// Package bench times solvers on their inputs, and compares the timings with
+// a saved baseline to spot solutions that have got slower.
+package bench
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+)
+
+// The steps timed for each day.
+const (
+	StepParse = "parse"
+	StepPart1 = "part1"
+	StepPart2 = "part2"
+)
+
+// A Result is the timing of one step of one day's solver.
+type Result struct {
+	Year        int    `json:"year"`
+	Day         int    `json:"day"`
+	Step        string `json:"step"`
+	N           int    `json:"n"`             // how many times the step was run
+	NsPerOp     int64  `json:"ns_per_op"`     // average time per run
+	AllocsPerOp int64  `json:"allocs_per_op"` // average allocations per run
+	BytesPerOp  int64  `json:"bytes_per_op"`  // average bytes allocated per run
+}
+
+func (r Result) key() string {
+	return fmt.Sprintf("%d/%d/%s", r.Year, r.Day, r.Step)
+}
+
+// Measure runs f over and over until at least minTime has passed, the way
+// the testing package runs benchmarks, and reports the average cost of one
+// run. It stops at the first error f returns.
+func Measure(f func() error, minTime time.Duration) (Result, error) {
+	// One run up front, to catch errors and warm things up.
+	if err := f(); err != nil {
+		return Result{}, err
+	}
+
+	n := 1
+	for {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+
+		for i := 0; i < n; i++ {
+			if err := f(); err != nil {
+				return Result{}, err
+			}
+		}
+
+		elapsed := time.Since(start)
+		runtime.ReadMemStats(&after)
+
+		if elapsed >= minTime || n >= 1e9 {
+			return Result{
+				N:           n,
+				NsPerOp:     elapsed.Nanoseconds() / int64(n),
+				AllocsPerOp: int64(after.Mallocs-before.Mallocs) / int64(n),
+				BytesPerOp:  int64(after.TotalAlloc-before.TotalAlloc) / int64(n),
+			}, nil
+		}
+
+		// Guess how many runs will fill minTime, but don't grow too fast in
+		// case the early runs were unusually quick.
+		next := n * 100
+		if elapsed > 0 {
+			next = int(int64(n) * int64(minTime) * 6 / 5 / elapsed.Nanoseconds())
+		}
+		if next > n*100 {
+			next = n * 100
+		}
+		if next <= n {
+			next = n + 1
+		}
+		n = next
+	}
+}
+
+// Solver times parsing, if the solver can parse on its own, and each part of
+// a day's solver on the given input.
+func Solver(year, day int, s aoc.Solver, input []byte, minTime time.Duration) ([]Result, error) {
+	type step struct {
+		name string
+		run  func() error
+	}
+
+	var steps []step
+	if p, ok := s.(aoc.Parser); ok {
+		steps = append(steps, step{StepParse, func() error {
+			return p.Parse(bytes.NewReader(input))
+		}})
+	}
+	steps = append(steps,
+		step{StepPart1, func() error {
+			_, err := s.Part1(bytes.NewReader(input))
+			return err
+		}},
+		step{StepPart2, func() error {
+			_, err := s.Part2(bytes.NewReader(input))
+			return err
+		}},
+	)
+
+	var results []Result
+	for _, step := range steps {
+		r, err := Measure(step.run, minTime)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", step.name, err)
+		}
+		r.Year, r.Day, r.Step = year, day, step.name
+		results = append(results, r)
+	}
+
+	return results, nil
+}
+
+// A Baseline holds earlier results to compare new ones with.
+type Baseline map[string]Result
+
+// NewBaseline makes a Baseline from a set of results.
+func NewBaseline(results []Result) Baseline {
+	b := Baseline{}
+	for _, r := range results {
+		b[r.key()] = r
+	}
+	return b
+}
+
+// Load reads a baseline saved by Save.
+func Load(path string) (Baseline, error) {
+	dat, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	if err := json.Unmarshal(dat, &results); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return NewBaseline(results), nil
+}
+
+// Save writes results to a file, to be used as a baseline later.
+func Save(path string, results []Result) error {
+	dat, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(dat, '\n'), 0o644)
+}
+
+// A Comparison is a result set against the same step in the baseline.
+type Comparison struct {
+	Base      Result
+	Found     bool    // whether the baseline has this step at all
+	Delta     float64 // change in time per run, as a fraction of the baseline
+	Regressed bool    // slower, or allocating more, than allowed
+}
+
+// Compare checks a result against the baseline. The step has regressed if it
+// takes more than threshold longer (0.2 allows 20% slower), or allocates more
+// than threshold more often, than it used to.
+func (b Baseline) Compare(r Result, threshold float64) Comparison {
+	base, ok := b[r.key()]
+	if !ok {
+		return Comparison{}
+	}
+
+	c := Comparison{Base: base, Found: true}
+	if base.NsPerOp > 0 {
+		c.Delta = float64(r.NsPerOp-base.NsPerOp) / float64(base.NsPerOp)
+	}
+	c.Regressed = c.Delta > threshold || float64(r.AllocsPerOp) > float64(base.AllocsPerOp)*(1+threshold)
+	return c
+}
@@ -0,0 +1,106 @@
+package bench
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+)
+
+func TestMeasure(t *testing.T) {
+	calls := 0
+	r, err := Measure(func() error {
+		calls++
+		time.Sleep(100 * time.Microsecond)
+		return nil
+	}, 5*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.N < 1 || calls < r.N+1 {
+		t.Errorf("N = %d after %d calls, want at least one warm up call more than N", r.N, calls)
+	}
+	if r.NsPerOp < int64(100*time.Microsecond) {
+		t.Errorf("NsPerOp = %d, want at least the time slept", r.NsPerOp)
+	}
+
+	boom := errors.New("boom")
+	if _, err := Measure(func() error { return boom }, time.Millisecond); err != boom {
+		t.Errorf("Measure error = %v, want %v", err, boom)
+	}
+}
+
+type fakeSolver struct{}
+
+func (fakeSolver) Part1(io.Reader) (aoc.Answer, error) { return "1", nil }
+func (fakeSolver) Part2(io.Reader) (aoc.Answer, error) { return "2", nil }
+
+type fakeParser struct{ fakeSolver }
+
+func (fakeParser) Parse(io.Reader) error { return nil }
+
+func TestSolverSteps(t *testing.T) {
+	for _, tt := range []struct {
+		s     aoc.Solver
+		steps []string
+	}{
+		{fakeSolver{}, []string{StepPart1, StepPart2}},
+		{fakeParser{}, []string{StepParse, StepPart1, StepPart2}},
+	} {
+		results, err := Solver(2020, 1, tt.s, nil, time.Microsecond)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var steps []string
+		for _, r := range results {
+			steps = append(steps, r.Step)
+		}
+		if len(steps) != len(tt.steps) {
+			t.Errorf("%T: steps %q, want %q", tt.s, steps, tt.steps)
+			continue
+		}
+		for i := range steps {
+			if steps[i] != tt.steps[i] {
+				t.Errorf("%T: steps %q, want %q", tt.s, steps, tt.steps)
+				break
+			}
+		}
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	old := []Result{
+		{Year: 2020, Day: 1, Step: StepPart1, NsPerOp: 1000, AllocsPerOp: 10},
+		{Year: 2020, Day: 1, Step: StepPart2, NsPerOp: 1000, AllocsPerOp: 10},
+	}
+	path := filepath.Join(t.TempDir(), "base.json")
+	if err := Save(path, old); err != nil {
+		t.Fatal(err)
+	}
+	base, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		r         Result
+		found     bool
+		regressed bool
+	}{
+		{Result{Year: 2020, Day: 1, Step: StepPart1, NsPerOp: 1100, AllocsPerOp: 10}, true, false},
+		{Result{Year: 2020, Day: 1, Step: StepPart1, NsPerOp: 1300, AllocsPerOp: 10}, true, true},
+		{Result{Year: 2020, Day: 1, Step: StepPart2, NsPerOp: 900, AllocsPerOp: 20}, true, true},
+		{Result{Year: 2020, Day: 2, Step: StepPart1, NsPerOp: 900, AllocsPerOp: 20}, false, false},
+	}
+	for _, tt := range tests {
+		c := base.Compare(tt.r, 0.2)
+		if c.Found != tt.found || c.Regressed != tt.regressed {
+			t.Errorf("%s: found %t, regressed %t; want %t, %t", tt.r.key(), c.Found, c.Regressed, tt.found, tt.regressed)
+		}
+	}
+}
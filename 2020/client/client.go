@@ -0,0 +1,140 @@
+// Package client talks to the Advent of Code website on behalf of a logged in
+// user. The site identifies users by their session cookie, which can be
+// copied from a browser after logging in.
+//
+// The base URL is configurable so the client can be pointed at a stand-in
+// server, which is how it is tested.
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the address of the real site.
+const DefaultBaseURL = "https://adventofcode.com"
+
+// DefaultMinInterval is how long we wait between requests by default, to go
+// easy on the site when fetching several days at once.
+const DefaultMinInterval = 3 * time.Second
+
+// userAgent identifies this tool to the site's operators, as they ask
+// automated tools to do.
+const userAgent = "github.com/tangledhelix/adventofcode2020 by tangledhelix"
+
+// ErrRateLimited is returned when the site says we've made too many requests.
+var ErrRateLimited = errors.New("rate limited by server")
+
+// ErrNotLoggedIn is returned when the site didn't accept the session cookie.
+var ErrNotLoggedIn = errors.New("session not accepted, is the cookie current?")
+
+// A Client makes requests to the site. Requests made through the same Client
+// are spaced at least MinInterval apart.
+type Client struct {
+	BaseURL     string        // site address, without a trailing slash
+	Session     string        // value of the session cookie
+	MinInterval time.Duration // minimum time between requests
+	HTTPClient  *http.Client  // nil means http.DefaultClient
+
+	last  time.Time // when the last request was made
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// New returns a Client for the real site using the given session cookie.
+func New(session string) *Client {
+	return &Client{
+		BaseURL:     DefaultBaseURL,
+		Session:     session,
+		MinInterval: DefaultMinInterval,
+	}
+}
+
+// Input downloads the puzzle input for a day.
+func (c *Client) Input(year, day int) ([]byte, error) {
+	req, err := http.NewRequest("GET", c.url(year, day, "input"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(resp, body); err != nil {
+		return nil, fmt.Errorf("fetching %d day %d input: %w", year, day, err)
+	}
+
+	return body, nil
+}
+
+func (c *Client) url(year, day int, page string) string {
+	u := fmt.Sprintf("%s/%d/day/%d", strings.TrimRight(c.BaseURL, "/"), year, day)
+	if page != "" {
+		u += "/" + page
+	}
+	return u
+}
+
+// do sends a request with our session and user agent, once enough time has
+// passed since the last one.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if c.Session == "" {
+		return nil, errors.New("no session cookie set")
+	}
+
+	c.wait()
+
+	req.AddCookie(&http.Cookie{Name: "session", Value: c.Session})
+	req.Header.Set("User-Agent", userAgent)
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	return hc.Do(req)
+}
+
+func (c *Client) wait() {
+	now, sleep := time.Now, time.Sleep
+	if c.now != nil {
+		now = c.now
+	}
+	if c.sleep != nil {
+		sleep = c.sleep
+	}
+
+	if !c.last.IsZero() {
+		if d := c.MinInterval - now().Sub(c.last); d > 0 {
+			sleep(d)
+		}
+	}
+	c.last = now()
+}
+
+func checkStatus(resp *http.Response, body []byte) error {
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized:
+		return ErrNotLoggedIn
+	}
+
+	msg := strings.TrimSpace(string(body))
+	if len(msg) > 200 {
+		msg = msg[:200] + "..."
+	}
+	return fmt.Errorf("server said %s: %s", resp.Status, msg)
+}
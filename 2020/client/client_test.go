@@ -0,0 +1,108 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newServer returns a stand-in for the site that serves an input for any day,
+// as long as the right session cookie is sent.
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/2020/day/6/input", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("session")
+		if err != nil || cookie.Value != "secret" {
+			http.Error(w, "Puzzle inputs differ by user.  Please log in to get your puzzle input.", http.StatusBadRequest)
+			return
+		}
+		if r.UserAgent() != userAgent {
+			t.Errorf("User-Agent = %q, want %q", r.UserAgent(), userAgent)
+		}
+		w.Write([]byte("abc\n\nab\n"))
+	})
+	mux.HandleFunc("/2020/day/7/input", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	})
+
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestInput(t *testing.T) {
+	ts := newServer(t)
+
+	c := New("secret")
+	c.BaseURL = ts.URL
+	c.MinInterval = 0
+
+	dat, err := c.Input(2020, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(dat) != "abc\n\nab\n" {
+		t.Errorf("Input = %q", dat)
+	}
+}
+
+func TestInputErrors(t *testing.T) {
+	ts := newServer(t)
+
+	tests := []struct {
+		session string
+		day     int
+		want    error
+	}{
+		{"wrong", 6, ErrNotLoggedIn},
+		{"secret", 7, ErrRateLimited},
+	}
+
+	for _, tt := range tests {
+		c := New(tt.session)
+		c.BaseURL = ts.URL
+		c.MinInterval = 0
+
+		_, err := c.Input(2020, tt.day)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("session %q day %d: got %v, want %v", tt.session, tt.day, err, tt.want)
+		}
+	}
+
+	c := New("secret")
+	c.BaseURL = ts.URL
+	if _, err := c.Input(2020, 8); err == nil {
+		t.Error("day 8: got no error for a missing page")
+	}
+}
+
+func TestMinInterval(t *testing.T) {
+	ts := newServer(t)
+
+	now := time.Date(2020, 12, 6, 0, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+
+	c := New("secret")
+	c.BaseURL = ts.URL
+	c.MinInterval = 3 * time.Second
+	c.now = func() time.Time { return now }
+	c.sleep = func(d time.Duration) {
+		slept = append(slept, d)
+		now = now.Add(d)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Input(2020, 6); err != nil {
+			t.Fatal(err)
+		}
+		now = now.Add(time.Second)
+	}
+
+	if len(slept) != 1 || slept[0] != 2*time.Second {
+		t.Errorf("slept %v, want [2s]", slept)
+	}
+}
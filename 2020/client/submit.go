@@ -0,0 +1,144 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+)
+
+// An Outcome is the site's verdict on a submitted answer.
+type Outcome int
+
+const (
+	Unknown    Outcome = iota // we couldn't make sense of the response
+	Correct                   // the answer was right
+	Incorrect                 // wrong, with no hint which way
+	TooHigh                   // wrong, and too high
+	TooLow                    // wrong, and too low
+	Wait                      // not checked; we answered too recently
+	WrongLevel                // not checked; that part isn't open or is already solved
+)
+
+var outcomeNames = [...]string{
+	Unknown:    "unknown",
+	Correct:    "correct",
+	Incorrect:  "incorrect",
+	TooHigh:    "too high",
+	TooLow:     "too low",
+	Wait:       "wait",
+	WrongLevel: "wrong level",
+}
+
+func (o Outcome) String() string {
+	if o < 0 || int(o) >= len(outcomeNames) {
+		return fmt.Sprintf("Outcome(%d)", int(o))
+	}
+	return outcomeNames[o]
+}
+
+// MarshalText stores an Outcome by name, so saved records stay readable.
+func (o Outcome) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+func (o *Outcome) UnmarshalText(text []byte) error {
+	for i, name := range outcomeNames {
+		if name == string(text) {
+			*o = Outcome(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown outcome %q", text)
+}
+
+// Wrong reports whether the outcome says the answer was checked and wrong.
+func (o Outcome) Wrong() bool {
+	return o == Incorrect || o == TooHigh || o == TooLow
+}
+
+// A Result is what the site said about a submitted answer.
+type Result struct {
+	Outcome Outcome
+	Wait    time.Duration // for Wait, how long until we may answer again
+	Message string        // the text of the response, stripped of markup
+}
+
+// Submit sends an answer for one part of a puzzle.
+func (c *Client) Submit(year, day, part int, answer aoc.Answer) (*Result, error) {
+	form := url.Values{
+		"level":  {strconv.Itoa(part)},
+		"answer": {answer.String()},
+	}
+	req, err := http.NewRequest("POST", c.url(year, day, "answer"), strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(resp, body); err != nil {
+		return nil, fmt.Errorf("submitting %d day %d part %d: %w", year, day, part, err)
+	}
+
+	return parseResult(string(body)), nil
+}
+
+var (
+	articleRE = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tagRE     = regexp.MustCompile(`<[^>]*>`)
+	spaceRE   = regexp.MustCompile(`\s+`)
+	waitRE    = regexp.MustCompile(`You have (?:(\d+)m )?(\d+)s left to wait`)
+)
+
+// parseResult works out the outcome from the response page. The answer is
+// reported in the page's <article>, in plain English.
+func parseResult(page string) *Result {
+	msg := page
+	if m := articleRE.FindStringSubmatch(page); m != nil {
+		msg = m[1]
+	}
+	msg = tagRE.ReplaceAllString(msg, "")
+	msg = strings.TrimSpace(spaceRE.ReplaceAllString(msg, " "))
+
+	r := &Result{Message: msg}
+	switch {
+	case strings.Contains(msg, "That's the right answer"):
+		r.Outcome = Correct
+	case strings.Contains(msg, "That's not the right answer"):
+		switch {
+		case strings.Contains(msg, "your answer is too high"):
+			r.Outcome = TooHigh
+		case strings.Contains(msg, "your answer is too low"):
+			r.Outcome = TooLow
+		default:
+			r.Outcome = Incorrect
+		}
+	case strings.Contains(msg, "You gave an answer too recently"):
+		r.Outcome = Wait
+		if m := waitRE.FindStringSubmatch(msg); m != nil {
+			mins, _ := strconv.Atoi(m[1])
+			secs, _ := strconv.Atoi(m[2])
+			r.Wait = time.Duration(mins)*time.Minute + time.Duration(secs)*time.Second
+		}
+	case strings.Contains(msg, "You don't seem to be solving the right level"):
+		r.Outcome = WrongLevel
+	}
+
+	return r
+}
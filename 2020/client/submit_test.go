@@ -0,0 +1,70 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+)
+
+func page(article string) string {
+	return "<html><body><main><article><p>" + article + "</p></article></main></body></html>"
+}
+
+func TestParseResult(t *testing.T) {
+	tests := []struct {
+		page string
+		want Outcome
+		wait time.Duration
+	}{
+		{page("That's the right answer! You are <em>one gold star</em> closer to saving your vacation."), Correct, 0},
+		{page("That's not the right answer; your answer is too high. Please wait one minute before trying again."), TooHigh, 0},
+		{page("That's not the right answer; your answer is too low."), TooLow, 0},
+		{page("That's not the right answer. If you're stuck, make sure you're using the full input data."), Incorrect, 0},
+		{page("You gave an answer too recently; you have to wait after submitting an answer before trying again. You have 32s left to wait."), Wait, 32 * time.Second},
+		{page("You gave an answer too recently; you have to wait after submitting an answer before trying again. You have 4m 5s left to wait."), Wait, 4*time.Minute + 5*time.Second},
+		{page("You don't seem to be solving the right level. Did you already complete it?"), WrongLevel, 0},
+		{page("Something else entirely."), Unknown, 0},
+	}
+
+	for _, tt := range tests {
+		r := parseResult(tt.page)
+		if r.Outcome != tt.want || r.Wait != tt.wait {
+			t.Errorf("%q: got %s (wait %v), want %s (wait %v)", r.Message, r.Outcome, r.Wait, tt.want, tt.wait)
+		}
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/2020/day/5/answer" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.FormValue("level") != "2" {
+			t.Errorf("level = %q, want 2", r.FormValue("level"))
+		}
+		if r.FormValue("answer") == "669" {
+			w.Write([]byte(page("That's the right answer!")))
+		} else {
+			w.Write([]byte(page("That's not the right answer; your answer is too low.")))
+		}
+	}))
+	defer ts.Close()
+
+	c := New("secret")
+	c.BaseURL = ts.URL
+	c.MinInterval = 0
+
+	for answer, want := range map[string]Outcome{"669": Correct, "600": TooLow} {
+		r, err := c.Submit(2020, 5, 2, aoc.Answer(answer))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Outcome != want {
+			t.Errorf("Submit(%s) = %s, want %s", answer, r.Outcome, want)
+		}
+	}
+}
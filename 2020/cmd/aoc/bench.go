@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+	"github.com/tangledhelix/adventofcode2020/bench"
+)
+
+func benchCommand(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	provider := addInputFlags(fs)
+	addSolverFlags(fs)
+	minTime := fs.Duration("time", 500*time.Millisecond, "how long to run each step for")
+	save := fs.String("save", "", "save the results to this file, for use as a baseline")
+	baseline := fs.String("baseline", "", "compare the results with this saved baseline")
+	threshold := fs.Float64("threshold", 0.2, "how much slower than the baseline counts as a regression")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	year, days, err := parseYearDays(positional, "aoc bench [-time d] [-save file] [-baseline file] [-threshold f] <year> [days]")
+	if err != nil {
+		return err
+	}
+
+	p, err := provider()
+	if err != nil {
+		return err
+	}
+
+	var base bench.Baseline
+	if *baseline != "" {
+		base, err = bench.Load(*baseline)
+		if err != nil {
+			return err
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(w, "day\tstep\ttime/op\tallocs/op\tbytes/op\t")
+	if base != nil {
+		fmt.Fprint(w, "vs baseline\t")
+	}
+	fmt.Fprintln(w)
+
+	var all []bench.Result
+	regressions := 0
+
+	for _, day := range days {
+		solver, ok := aoc.Lookup(year, day)
+		if !ok {
+			return fmt.Errorf("no solution for %d day %d", year, day)
+		}
+		dat, path, err := p.Read(year, day)
+		if err != nil {
+			return err
+		}
+
+		results, err := bench.Solver(year, day, solver, dat, *minTime)
+		if err != nil {
+			return solverError(year, day, 0, path, err)
+		}
+
+		for _, r := range results {
+			fmt.Fprintf(w, "%02d\t%s\t%v\t%d\t%d\t", r.Day, r.Step, time.Duration(r.NsPerOp), r.AllocsPerOp, r.BytesPerOp)
+			if base != nil {
+				c := base.Compare(r, *threshold)
+				switch {
+				case !c.Found:
+					fmt.Fprint(w, "new\t")
+				case c.Regressed:
+					fmt.Fprintf(w, "%+.1f%% REGRESSED\t", c.Delta*100)
+					regressions++
+				default:
+					fmt.Fprintf(w, "%+.1f%%\t", c.Delta*100)
+				}
+			}
+			fmt.Fprintln(w)
+		}
+
+		all = append(all, results...)
+	}
+	w.Flush()
+
+	if *save != "" {
+		if err := bench.Save(*save, all); err != nil {
+			return err
+		}
+	}
+
+	if regressions > 0 {
+		return fmt.Errorf("%d steps regressed against %s", regressions, *baseline)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tangledhelix/adventofcode2020/client"
+)
+
+func fetchCommand(args []string) error {
+	fs := flag.NewFlagSet("fetch", flag.ExitOnError)
+	provider := addInputFlags(fs)
+	newClient := addClientFlags(fs)
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	year, days, err := parseYearDays(positional, "aoc fetch [-cache path] [-user name] [-url url] [-session-file path] <year> [days]")
+	if err != nil {
+		return err
+	}
+
+	p, err := provider()
+	if err != nil {
+		return err
+	}
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	for _, day := range days {
+		// Inputs never change once issued, so there's no reason to ask for
+		// one again.
+		cached, err := p.Cached(year, day)
+		if err != nil {
+			return err
+		}
+		if cached {
+			path, _ := p.CachePath(year, day)
+			fmt.Printf("%d day %02d: already cached in %s, not fetching\n", year, day, path)
+			continue
+		}
+
+		dat, err := c.Input(year, day)
+		if err != nil {
+			return err
+		}
+		if err := p.Store(year, day, dat); err != nil {
+			return err
+		}
+
+		path, _ := p.CachePath(year, day)
+		fmt.Printf("%d day %02d: saved %d bytes to %s\n", year, day, len(dat), path)
+	}
+
+	return nil
+}
+
+// addClientFlags adds the flags for talking to the site to fs. It returns a
+// function that builds the Client they describe, to be called once fs has
+// been parsed. The session cookie comes from $AOC_SESSION unless a file
+// holding it is named.
+func addClientFlags(fs *flag.FlagSet) func() (*client.Client, error) {
+	baseURL := fs.String("url", client.DefaultBaseURL, "base URL of the site")
+	sessionFile := fs.String("session-file", "", "file holding the session cookie (default $AOC_SESSION)")
+	interval := fs.Duration("interval", client.DefaultMinInterval, "minimum time between requests")
+
+	return func() (*client.Client, error) {
+		session := os.Getenv("AOC_SESSION")
+		if *sessionFile != "" {
+			dat, err := os.ReadFile(*sessionFile)
+			if err != nil {
+				return nil, err
+			}
+			session = string(dat)
+		}
+
+		session = strings.TrimSpace(session)
+		if session == "" {
+			return nil, errors.New("no session cookie; set $AOC_SESSION or use -session-file")
+		}
+
+		c := client.New(session)
+		c.BaseURL = *baseURL
+		c.MinInterval = *interval
+		return c, nil
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFetchCommand(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/2020/day/6/input" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("abc\n"))
+	}))
+	defer ts.Close()
+
+	cache := t.TempDir()
+	t.Setenv("AOC_SESSION", "secret")
+	args := []string{"-cache", cache, "-user", "alice", "-url", ts.URL, "-interval", "0", "2020", "6"}
+
+	if err := fetchCommand(args); err != nil {
+		t.Fatal(err)
+	}
+	dat, err := os.ReadFile(filepath.Join(cache, "2020", "day06", "alice.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(dat) != "abc\n" {
+		t.Errorf("cached input = %q", dat)
+	}
+
+	// A second fetch must not go back to the server.
+	if err := fetchCommand(args); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Errorf("server got %d requests, want 1", requests)
+	}
+}
@@ -0,0 +1,166 @@
+// Command aoc runs the Advent of Code solutions in this module.
+//
+// Usage:
+//
+//	aoc run [-dir path] [-cache path] [-user name] <year> [days]
+//
+// Days may be a single day (6), a range (1-6), a comma separated list of
+// either (1,3,5-6), or left out entirely to run every day we have a solution
+// for.
+//
+//	aoc fetch [-cache path] [-user name] [-url url] [-session-file path] <year> [days]
+//	aoc submit [-cache path] [-user name] [-url url] [-session-file path] [-ledger path] <year> <day> <part> [answer]
+//	aoc bench [-time d] [-save file] [-baseline file] [-threshold f] <year> [days]
+//
+// Inputs are read from the per-user input cache (see package inputs), or
+// from the input.txt checked in under -dir when no user is picked with -user
+// or $AOC_USER.
+//
+// Fetch downloads inputs into the cache, using the session cookie in
+// $AOC_SESSION or the named file. Inputs already in the cache are never
+// downloaded again.
+//
+// Submit sends an answer, solving the puzzle for one if none is given. Every
+// attempt is kept in a ledger (see package ledger), and answers the ledger
+// shows can't be right are refused without asking the site.
+//
+// Days can add commands of their own, listed by "aoc help".
+//
+// Some solvers have options of their own, for example -target and -k for
+// 2020 day 1. Run, submit and bench all accept them.
+//
+// Bench times parsing and each part for every day, and compares the times
+// with a baseline saved by an earlier run to flag regressions.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+	"github.com/tangledhelix/adventofcode2020/inputs"
+
+	_ "github.com/tangledhelix/adventofcode2020/day01"
+	_ "github.com/tangledhelix/adventofcode2020/day02"
+	_ "github.com/tangledhelix/adventofcode2020/day03"
+	_ "github.com/tangledhelix/adventofcode2020/day04"
+	_ "github.com/tangledhelix/adventofcode2020/day05"
+	_ "github.com/tangledhelix/adventofcode2020/day06"
+)
+
+const usageText = `usage: aoc <command> [arguments]
+
+commands:
+  run [-dir path] [-cache path] [-user name] <year> [days]
+        run solutions, e.g. "aoc run 2020 1-6"
+  fetch [-cache path] [-user name] [-url url] [-session-file path] <year> [days]
+        download inputs into the cache, e.g. "aoc fetch 2020 6"
+  submit [-cache path] [-user name] [-url url] [-session-file path] [-ledger path] <year> <day> <part> [answer]
+        submit an answer, e.g. "aoc submit 2020 5 1"
+  bench [-time d] [-save file] [-baseline file] [-threshold f] <year> [days]
+        time solutions, e.g. "aoc bench -baseline base.json 2020"
+`
+
+func usage() {
+	fmt.Fprint(os.Stderr, usageText)
+
+	// Then the commands the days brought with them
+	for _, c := range aoc.Commands() {
+		fmt.Fprintf(os.Stderr, "  %s %s\n        %s\n", c.Name, c.Usage, c.Summary)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "run":
+		err = runCommand(os.Args[2:])
+	case "fetch":
+		err = fetchCommand(os.Args[2:])
+	case "submit":
+		err = submitCommand(os.Args[2:])
+	case "bench":
+		err = benchCommand(os.Args[2:])
+	case "help", "-h", "-help", "--help":
+		usage()
+		return
+	default:
+		c, ok := aoc.LookupCommand(os.Args[1])
+		if !ok {
+			fmt.Fprintf(os.Stderr, "aoc: unknown command %q\n", os.Args[1])
+			usage()
+			os.Exit(2)
+		}
+		err = c.Run(os.Args[2:], os.Stdout)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "aoc:", err)
+
+		// Show where in the input a parse error happened
+		var pe *aoc.ParseError
+		if errors.As(err, &pe) {
+			if caret := pe.Caret(); caret != "" {
+				fmt.Fprintln(os.Stderr, caret)
+			}
+		}
+
+		os.Exit(1)
+	}
+}
+
+// parseArgs parses flags that may appear before, between or after the
+// positional arguments, which the flag package on its own doesn't allow. It
+// returns the positional arguments in order.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// addInputFlags adds the flags choosing where puzzle inputs come from to fs.
+// It returns a function that builds the Provider they describe, to be called
+// once fs has been parsed.
+func addInputFlags(fs *flag.FlagSet) func() (*inputs.Provider, error) {
+	dir := fs.String("dir", ".", "directory holding the dayNN folders with checked in inputs")
+	cache := fs.String("cache", "", "input cache directory (default $AOC_CACHE_DIR, or under the user cache directory)")
+	user := fs.String("user", "", "whose inputs to use (default $AOC_USER, or the checked in inputs)")
+
+	return func() (*inputs.Provider, error) {
+		p, err := inputs.NewProvider(*dir)
+		if err != nil {
+			return nil, err
+		}
+		if *cache != "" {
+			p.CacheDir = *cache
+		}
+		if *user != "" {
+			p.User = *user
+		}
+		return p, nil
+	}
+}
+
+// addSolverFlags adds the options of every solver that has any to fs.
+func addSolverFlags(fs *flag.FlagSet) {
+	for _, s := range aoc.All() {
+		if f, ok := s.(aoc.Flagger); ok {
+			f.Flags(fs)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+)
+
+func runCommand(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	provider := addInputFlags(fs)
+	addSolverFlags(fs)
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	year, days, err := parseYearDays(positional, "aoc run [-dir path] [-cache path] [-user name] <year> [days]")
+	if err != nil {
+		return err
+	}
+
+	p, err := provider()
+	if err != nil {
+		return err
+	}
+
+	for _, day := range days {
+		solver, ok := aoc.Lookup(year, day)
+		if !ok {
+			return fmt.Errorf("no solution for %d day %d", year, day)
+		}
+
+		dat, path, err := p.Read(year, day)
+		if err != nil {
+			return err
+		}
+
+		part1, part2, failed, err := solveBoth(solver, dat)
+		if err != nil {
+			return solverError(year, day, failed, path, err)
+		}
+
+		fmt.Printf("%d day %02d: part 1 = %s, part 2 = %s\n", year, day, part1, part2)
+	}
+
+	return nil
+}
+
+// solveBoth solves both parts of a day over its input, reading it only once
+// if the solver is an aoc.BothSolver. On an error, it says which part failed,
+// or 0 if it can't tell.
+func solveBoth(solver aoc.Solver, dat []byte) (part1, part2 aoc.Answer, failed int, err error) {
+	if both, ok := solver.(aoc.BothSolver); ok {
+		part1, part2, err = both.Solve(bytes.NewReader(dat))
+		return part1, part2, 0, err
+	}
+
+	if part1, err = solver.Part1(bytes.NewReader(dat)); err != nil {
+		return "", "", 1, err
+	}
+	if part2, err = solver.Part2(bytes.NewReader(dat)); err != nil {
+		return "", "", 2, err
+	}
+	return part1, part2, 0, nil
+}
+
+// solverError adds what we know about where the input came from to an error
+// returned by a solver, and which part it was solving if part isn't 0. A
+// parse error that already names a file is about some other file the solver
+// read, like one named by a flag.
+func solverError(year, day, part int, path string, err error) error {
+	var pe *aoc.ParseError
+	if errors.As(err, &pe) {
+		pe.Year, pe.Day = year, day
+		if pe.File == "" {
+			pe.File = path
+		}
+		return err
+	}
+	if part == 0 {
+		return fmt.Errorf("%d day %02d: %w", year, day, err)
+	}
+	return fmt.Errorf("%d day %02d part %d: %w", year, day, part, err)
+}
+
+// parseYearDays reads the "<year> [days]" arguments most commands take. With
+// no days given, it picks every day that has a solver.
+func parseYearDays(positional []string, usage string) (int, []int, error) {
+	if len(positional) < 1 || len(positional) > 2 {
+		return 0, nil, fmt.Errorf("usage: %s", usage)
+	}
+
+	year, err := strconv.Atoi(positional[0])
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid year %q", positional[0])
+	}
+
+	days := aoc.Days(year)
+	if len(positional) == 2 {
+		days, err = parseDays(positional[1])
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+	if len(days) == 0 {
+		return 0, nil, fmt.Errorf("no solutions for %d", year)
+	}
+
+	return year, days, nil
+}
+
+// parseDays turns a day list like "1,3,5-6" into the days it names, in the
+// order given.
+func parseDays(spec string) ([]int, error) {
+	var days []int
+
+	for _, field := range strings.Split(spec, ",") {
+		low, high := field, field
+		if i := strings.Index(field, "-"); i >= 0 {
+			low, high = field[:i], field[i+1:]
+		}
+
+		first, err := parseDay(low)
+		if err != nil {
+			return nil, err
+		}
+		last, err := parseDay(high)
+		if err != nil {
+			return nil, err
+		}
+		if first > last {
+			return nil, fmt.Errorf("invalid day range %q", field)
+		}
+
+		for day := first; day <= last; day++ {
+			days = append(days, day)
+		}
+	}
+
+	return days, nil
+}
+
+// Puzzles are released on days 1 through 25.
+func parseDay(s string) (int, error) {
+	day, err := strconv.Atoi(s)
+	if err != nil || day < 1 || day > 25 {
+		return 0, fmt.Errorf("invalid day %q", s)
+	}
+	return day, nil
+}
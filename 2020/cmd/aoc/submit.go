@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+	"github.com/tangledhelix/adventofcode2020/client"
+	"github.com/tangledhelix/adventofcode2020/ledger"
+)
+
+const submitUsage = "aoc submit [-cache path] [-user name] [-url url] [-session-file path] [-ledger path] <year> <day> <part> [answer]"
+
+func submitCommand(args []string) error {
+	fs := flag.NewFlagSet("submit", flag.ExitOnError)
+	provider := addInputFlags(fs)
+	addSolverFlags(fs)
+	newClient := addClientFlags(fs)
+	ledgerPath := fs.String("ledger", "", "answer ledger file (default $AOC_LEDGER, or under the user cache directory)")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 3 || len(positional) > 4 {
+		return fmt.Errorf("usage: %s", submitUsage)
+	}
+
+	year, err := strconv.Atoi(positional[0])
+	if err != nil {
+		return fmt.Errorf("invalid year %q", positional[0])
+	}
+	day, err := parseDay(positional[1])
+	if err != nil {
+		return err
+	}
+	part, err := strconv.Atoi(positional[2])
+	if err != nil || part < 1 || part > 2 {
+		return fmt.Errorf("invalid part %q, want 1 or 2", positional[2])
+	}
+
+	p, err := provider()
+	if err != nil {
+		return err
+	}
+
+	// Without an answer on the command line, solve the puzzle for one.
+	var answer aoc.Answer
+	if len(positional) == 4 {
+		answer = aoc.Answer(positional[3])
+	} else {
+		answer, err = solvePart(p.Read, year, day, part)
+		if err != nil {
+			return err
+		}
+	}
+
+	if *ledgerPath == "" {
+		*ledgerPath = os.Getenv("AOC_LEDGER")
+	}
+	if *ledgerPath == "" {
+		*ledgerPath, err = ledger.DefaultPath()
+		if err != nil {
+			return err
+		}
+	}
+	l, err := ledger.Open(*ledgerPath)
+	if err != nil {
+		return err
+	}
+
+	user := p.UserName()
+	if err := l.Check(user, year, day, part, answer, time.Now()); err != nil {
+		return err
+	}
+
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+	result, err := c.Submit(year, day, part, answer)
+	if err != nil {
+		return err
+	}
+
+	err = l.Record(ledger.Entry{
+		Time:    time.Now(),
+		User:    user,
+		Year:    year,
+		Day:     day,
+		Part:    part,
+		Answer:  answer,
+		Outcome: result.Outcome,
+		Wait:    result.Wait,
+		Message: result.Message,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d day %02d part %d: %s is %s\n", year, day, part, answer, result.Outcome)
+	if result.Message != "" {
+		fmt.Println(result.Message)
+	}
+	if result.Outcome != client.Correct {
+		return fmt.Errorf("answer not accepted (%s)", result.Outcome)
+	}
+
+	return nil
+}
+
+// solvePart runs one part of a day's solver over its input.
+func solvePart(read func(year, day int) ([]byte, string, error), year, day, part int) (aoc.Answer, error) {
+	solver, ok := aoc.Lookup(year, day)
+	if !ok {
+		return "", fmt.Errorf("no solution for %d day %d", year, day)
+	}
+
+	dat, path, err := read(year, day)
+	if err != nil {
+		return "", err
+	}
+
+	// A BothSolver's parts are meant to be solved together.
+	if both, ok := solver.(aoc.BothSolver); ok {
+		part1, part2, err := both.Solve(bytes.NewReader(dat))
+		if err != nil {
+			return "", solverError(year, day, 0, path, err)
+		}
+		if part == 2 {
+			return part2, nil
+		}
+		return part1, nil
+	}
+
+	solve := solver.Part1
+	if part == 2 {
+		solve = solver.Part2
+	}
+
+	answer, err := solve(bytes.NewReader(dat))
+	if err != nil {
+		return "", solverError(year, day, part, path, err)
+	}
+	return answer, nil
+}
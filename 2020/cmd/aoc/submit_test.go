@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/tangledhelix/adventofcode2020/ledger"
+)
+
+func TestSubmitCommand(t *testing.T) {
+	var submitted []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		submitted = append(submitted, r.FormValue("answer"))
+		if r.FormValue("answer") == "669" {
+			w.Write([]byte("<article><p>That's the right answer!</p></article>"))
+		} else {
+			w.Write([]byte("<article><p>That's not the right answer; your answer is too low.</p></article>"))
+		}
+	}))
+	defer ts.Close()
+
+	t.Setenv("AOC_SESSION", "secret")
+	t.Setenv("AOC_USER", "")
+	flags := []string{
+		"-dir", "../..", "-cache", t.TempDir(), "-url", ts.URL, "-interval", "0",
+		"-ledger", filepath.Join(t.TempDir(), "answers.jsonl"),
+	}
+	submit := func(args ...string) error {
+		return submitCommand(append(append([]string{}, flags...), args...))
+	}
+
+	if err := submit("2020", "5", "2", "600"); err == nil {
+		t.Error("wrong answer: got no error")
+	}
+
+	// The ledger knows 600 was too low, so 500 must be refused without
+	// asking the server.
+	var r *ledger.Refusal
+	if err := submit("2020", "5", "2", "500"); !errors.As(err, &r) {
+		t.Errorf("answer below a known bound: got %v, want a refusal", err)
+	}
+
+	// With no answer given, the solver provides one.
+	if err := submit("2020", "5", "2"); err != nil {
+		t.Error(err)
+	}
+
+	if len(submitted) != 2 || submitted[0] != "600" || submitted[1] != "669" {
+		t.Errorf("server got answers %q, want [600 669]", submitted)
+	}
+}
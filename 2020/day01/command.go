@@ -0,0 +1,112 @@
+package day01
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+	"github.com/tangledhelix/adventofcode2020/input"
+)
+
+func init() {
+	aoc.RegisterCommand(aoc.Command{
+		Name:    "expenses",
+		Usage:   "sums [-k n] [-target n] [-count] [file] | stream [-target n] [-limit n] [file]",
+		Summary: "list every set of expense entries adding up to the target",
+		Run:     runExpenses,
+	})
+}
+
+func runExpenses(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: aoc expenses sums|stream [flags] [file]")
+	}
+
+	switch args[0] {
+	case "sums":
+		return runSums(args[1:], stdout)
+	case "stream":
+		return runStream(args[1:], stdout)
+	default:
+		return fmt.Errorf("unknown expenses command %q", args[0])
+	}
+}
+
+// runSums lists every combination of entries adding up to the target, so an
+// expense report with more than one answer can be spotted and looked into.
+func runSums(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("expenses sums", flag.ContinueOnError)
+	k := fs.Int("k", 2, "number of entries to add up")
+	target := fs.Int("target", 2020, "sum the entries must add up to")
+	count := fs.Bool("count", false, "only count the combinations, don't list them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return errors.New("usage: aoc expenses sums [-k n] [-target n] [-count] [file]")
+	}
+
+	expenses, err := input.ReadFile(fs.Arg(0), readExpenses)
+	if err != nil {
+		return err
+	}
+
+	if *count {
+		fmt.Fprintln(stdout, CountSums(expenses, *k, *target))
+		return nil
+	}
+
+	all := AllSums(expenses, *k, *target)
+	for _, c := range all {
+		lines := make([]string, len(c.Positions))
+		values := make([]string, len(c.Values))
+		for i := range c.Positions {
+			lines[i] = strconv.Itoa(c.Positions[i] + 1)
+			values[i] = strconv.Itoa(c.Values[i])
+		}
+		fmt.Fprintf(stdout, "lines %s: %s = %d, product %d\n",
+			strings.Join(lines, ","), strings.Join(values, " + "), *target, Product(expenses, c.Positions))
+	}
+	fmt.Fprintf(stdout, "%d combinations\n", len(all))
+
+	return nil
+}
+
+// runStream reports pairs of entries adding up to the target as it comes
+// across them, for expense exports too big to read in whole.
+func runStream(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("expenses stream", flag.ContinueOnError)
+	target := fs.Int64("target", 2020, "sum the entries must add up to")
+	limit := fs.Int("limit", 1<<20, "most distinct values to remember, 0 for no limit")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return errors.New("usage: aoc expenses stream [-target n] [-limit n] [file]")
+	}
+
+	name := fs.Arg(0)
+	r, err := input.Open(name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	stats, err := StreamPairs(r, *target, *limit, func(m Match) error {
+		_, err := fmt.Fprintf(stdout, "lines %d,%d: %d + %d = %d\n", m.Lines[0], m.Lines[1], m.Values[0], m.Values[1], *target)
+		return err
+	})
+	if err := input.Named(err, name); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "%d lines, %d matches\n", stats.Lines, stats.Matches)
+	if stats.Evicted > 0 {
+		fmt.Fprintf(stdout, "%d values forgotten to stay within -limit %d, so matches may have been missed\n", stats.Evicted, *limit)
+	}
+	return nil
+}
@@ -50,75 +50,74 @@
  * 2020?
  */
 
-package main
+package day01
 
 import (
+	"flag"
 	"fmt"
 	"io"
-	"os"
-	"strings"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+	"github.com/tangledhelix/adventofcode2020/input"
 )
 
-func check(e error) {
-	if e != nil {
-		if e != io.EOF {
-			panic(e)
-		}
-	}
+func init() {
+	aoc.Register(2020, 1, newSolver())
 }
 
-func main() {
+// The solver looks for entries adding up to target. Part 1 looks for two and
+// part 2 for three, unless k says how many to look for in both parts.
+type solver struct {
+	target int
+	k      int
+}
 
-	// This is given (for this problem) because we know the input length
-	const expenseCount int = 200
+func newSolver() *solver {
+	return &solver{target: 2020}
+}
 
-	// Read input file
-	dat, err := os.ReadFile("input.txt")
-	check(err)
+func (s *solver) Flags(fs *flag.FlagSet) {
+	fs.IntVar(&s.target, "target", s.target, "day 1: sum the expense entries must add up to")
+	fs.IntVar(&s.k, "k", s.k, "day 1: number of expense entries to find in both parts (default 2 for part 1, 3 for part 2)")
+}
 
-	// Convert string contents to integers
-	raw_expenses := strings.Split(string(dat), "\n")
-	var expenses [expenseCount]int
+func (s *solver) Parse(input io.Reader) error {
+	_, err := readExpenses(input)
+	return err
+}
 
-	for i := 0; i < len(raw_expenses); i++ {
-		var num int
-		n, err := fmt.Sscanf(raw_expenses[i], "%d", &num)
-		check(err)
+// Read the expense report, one number per line
+func readExpenses(r io.Reader) ([]int, error) {
+	return input.Ints(r)
+}
 
-		// Store a number, but only if one was returned.
-		// If we are at EOF, then n will be 0.
-		if n == 1 {
-			expenses[i] = num
-		}
+// Look for k entries which sum to the target, and return their product.
+func (s *solver) solve(input io.Reader, k int) (aoc.Answer, error) {
+	if s.k != 0 {
+		k = s.k
 	}
 
-	// Part 1 - look for a pair of numbers which sum to 2020, and return their
-	// product.
-
-	var found bool = false
+	expenses, err := readExpenses(input)
+	if err != nil {
+		return "", err
+	}
 
-	for i := 0; i < len(expenses) && !found; i++ {
-		for j := 0; j < len(expenses) && !found; j++ {
-			if expenses[i]+expenses[j] == 2020 {
-				fmt.Println("Answer 1 =", expenses[i]*expenses[j])
-				found = true
-			}
-		}
+	found, ok := FindSum(expenses, k, s.target)
+	if !ok {
+		return "", fmt.Errorf("no %d entries sum to %d", k, s.target)
 	}
 
-	// Repeat for part 2 - now looking for 3 numbers that sum to 2020. Again,
-	// return their product.
+	return aoc.Int(Product(expenses, found)), nil
+}
 
-	found = false
+// Part 1 - look for a pair of numbers which sum to 2020, and return their
+// product.
+func (s *solver) Part1(input io.Reader) (aoc.Answer, error) {
+	return s.solve(input, 2)
+}
 
-	for i := 0; i < len(expenses) && !found; i++ {
-		for j := 0; j < len(expenses) && !found; j++ {
-			for k := 0; k < len(expenses) && !found; k++ {
-				if expenses[i]+expenses[j]+expenses[k] == 2020 {
-					fmt.Println("Answer 2 =", expenses[i]*expenses[j]*expenses[k])
-					found = true
-				}
-			}
-		}
-	}
+// Repeat for part 2 - now looking for 3 numbers that sum to 2020. Again,
+// return their product.
+func (s *solver) Part2(input io.Reader) (aoc.Answer, error) {
+	return s.solve(input, 3)
 }
@@ -0,0 +1,34 @@
+package day01
+
+import (
+	"testing"
+
+	"github.com/tangledhelix/adventofcode2020/aoc/aoctest"
+)
+
+func TestExamples(t *testing.T) {
+	aoctest.Run(t, newSolver(), []aoctest.Case{
+		{
+			Name:  "expense report",
+			Input: "1721\n979\n366\n299\n675\n1456\n",
+			Part1: "514579",
+			Part2: "241861950",
+		},
+	})
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, newSolver(), "1016964", "182588480")
+}
+
+func BenchmarkParse(b *testing.B) {
+	aoctest.BenchmarkParse(b, newSolver())
+}
+
+func BenchmarkPart1(b *testing.B) {
+	aoctest.BenchmarkPart(b, newSolver().Part1)
+}
+
+func BenchmarkPart2(b *testing.B) {
+	aoctest.BenchmarkPart(b, newSolver().Part2)
+}
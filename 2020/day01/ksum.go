@@ -0,0 +1,311 @@
+package day01
+
+import "sort"
+
+// FindSum looks for k distinct entries of nums (distinct by position, so a
+// value that appears twice may be used twice) that add up to target. It
+// returns the positions of the entries it found, in ascending order, or false
+// if there aren't any.
+//
+// Pairs are found in one pass with a map of the values seen so far. For three
+// or more entries the values are sorted, and each choice of the first k-2
+// entries is finished off by walking two pointers in from both ends of the
+// rest, which takes O(n^(k-1)) time instead of the O(n^k) of trying every
+// combination.
+func FindSum(nums []int, k, target int) ([]int, bool) {
+	switch {
+	case k < 1 || k > len(nums):
+		return nil, false
+	case k == 1:
+		for i, n := range nums {
+			if n == target {
+				return []int{i}, true
+			}
+		}
+		return nil, false
+	case k == 2:
+		seen := make(map[int]int, len(nums))
+		for i, n := range nums {
+			if j, ok := seen[target-n]; ok {
+				return []int{j, i}, true
+			}
+			if _, ok := seen[n]; !ok {
+				seen[n] = i
+			}
+		}
+		return nil, false
+	}
+
+	// Sort positions by value, so we can report the original positions.
+	order := make([]int, len(nums))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return nums[order[a]] < nums[order[b]] })
+
+	sorted := make([]int, len(nums))
+	for i, pos := range order {
+		sorted[i] = nums[pos]
+	}
+
+	picked := findSorted(sorted, 0, k, target, make([]int, 0, k))
+	if picked == nil {
+		return nil, false
+	}
+
+	found := make([]int, k)
+	for i, p := range picked {
+		found[i] = order[p]
+	}
+	sort.Ints(found)
+	return found, true
+}
+
+// findSorted finds k entries of sorted[start:] adding up to target, appending
+// their indexes in sorted to picked.
+func findSorted(sorted []int, start, k, target int, picked []int) []int {
+	if k == 2 {
+		lo, hi := start, len(sorted)-1
+		for lo < hi {
+			switch sum := sorted[lo] + sorted[hi]; {
+			case sum == target:
+				return append(picked, lo, hi)
+			case sum < target:
+				lo++
+			default:
+				hi--
+			}
+		}
+		return nil
+	}
+
+	for i := start; i <= len(sorted)-k; i++ {
+		// The same value in the same slot can only find the same sums.
+		if i > start && sorted[i] == sorted[i-1] {
+			continue
+		}
+		if found := findSorted(sorted, i+1, k-1, target-sorted[i], append(picked, i)); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Product multiplies together the entries of nums at the given positions.
+func Product(nums []int, positions []int) int {
+	product := 1
+	for _, p := range positions {
+		product *= nums[p]
+	}
+	return product
+}
+
+// A Combination is a set of entries that add up to the target.
+type Combination struct {
+	Positions []int // positions of the entries, ascending
+	Values    []int // the entries, in the same order
+}
+
+// valueGroup is one distinct value of a list, and where it appears.
+type valueGroup struct {
+	value     int
+	positions []int
+}
+
+// groupValues returns the distinct values of nums, ascending, with the
+// positions each appears at.
+func groupValues(nums []int) []valueGroup {
+	byValue := make(map[int][]int)
+	for i, n := range nums {
+		byValue[n] = append(byValue[n], i)
+	}
+
+	groups := make([]valueGroup, 0, len(byValue))
+	for v, positions := range byValue {
+		groups = append(groups, valueGroup{v, positions})
+	}
+	sort.Slice(groups, func(a, b int) bool { return groups[a].value < groups[b].value })
+	return groups
+}
+
+// eachMultiset calls fn with every multiset of k values from groups adding up
+// to target, as a count of how many times each group is used. Values can be
+// used as many times as they appear. The counts slice is reused between
+// calls.
+//
+// This works on distinct values rather than entries, so that a list with a
+// lot of repeats doesn't cost more than one without. The last two values are
+// found by two pointers, as in FindSum.
+func eachMultiset(groups []valueGroup, k, target int, fn func(counts []int)) {
+	counts := make([]int, len(groups))
+
+	// available is how many more times group g may be used.
+	available := func(g int) int {
+		return len(groups[g].positions) - counts[g]
+	}
+
+	var walk func(start, k, target int)
+	walk = func(start, k, target int) {
+		if k == 1 {
+			// Only reached when k was 1 to begin with.
+			for g := start; g < len(groups); g++ {
+				if groups[g].value == target {
+					counts[g]++
+					fn(counts)
+					counts[g]--
+				}
+			}
+			return
+		}
+
+		if k == 2 {
+			lo, hi := start, len(groups)-1
+			for lo <= hi {
+				sum := groups[lo].value + groups[hi].value
+				switch {
+				case sum < target:
+					lo++
+				case sum > target:
+					hi--
+				default:
+					// lo and hi may be the same value, or one already
+					// picked, so make sure there are enough of them.
+					counts[lo]++
+					counts[hi]++
+					if available(lo) >= 0 && available(hi) >= 0 {
+						fn(counts)
+					}
+					counts[lo]--
+					counts[hi]--
+					lo++
+					hi--
+				}
+			}
+			return
+		}
+
+		// Choose values in ascending order, so each multiset comes up once.
+		for g := start; g < len(groups); g++ {
+			if available(g) == 0 {
+				continue
+			}
+			counts[g]++
+			walk(g, k-1, target-groups[g].value)
+			counts[g]--
+		}
+	}
+
+	if k >= 1 {
+		walk(0, k, target)
+	}
+}
+
+// AllSums returns every set of k distinct entries of nums (distinct by
+// position) that adds up to target, ordered by their positions.
+//
+// There can be a great many of them when values repeat; CountSums counts them
+// without listing them.
+func AllSums(nums []int, k, target int) []Combination {
+	if k < 1 || k > len(nums) {
+		return nil
+	}
+
+	groups := groupValues(nums)
+	var all []Combination
+
+	eachMultiset(groups, k, target, func(counts []int) {
+		// Each value used c times can be any c of the positions it appears
+		// at, so expand every choice of positions for every value.
+		var expand func(g int, positions []int)
+		expand = func(g int, positions []int) {
+			for g < len(groups) && counts[g] == 0 {
+				g++
+			}
+			if g == len(groups) {
+				c := Combination{Positions: append([]int(nil), positions...)}
+				sort.Ints(c.Positions)
+				c.Values = make([]int, len(c.Positions))
+				for i, p := range c.Positions {
+					c.Values[i] = nums[p]
+				}
+				all = append(all, c)
+				return
+			}
+			eachChoice(groups[g].positions, counts[g], func(chosen []int) {
+				expand(g+1, append(positions, chosen...))
+			})
+		}
+		expand(0, make([]int, 0, k))
+	})
+
+	sort.Slice(all, func(a, b int) bool {
+		pa, pb := all[a].Positions, all[b].Positions
+		for i := range pa {
+			if pa[i] != pb[i] {
+				return pa[i] < pb[i]
+			}
+		}
+		return false
+	})
+	return all
+}
+
+// eachChoice calls fn with every way of choosing c items from items, in
+// order. The slice passed to fn is reused between calls.
+func eachChoice(items []int, c int, fn func(chosen []int)) {
+	chosen := make([]int, 0, c)
+
+	var walk func(start int)
+	walk = func(start int) {
+		if len(chosen) == c {
+			fn(chosen)
+			return
+		}
+		for i := start; i <= len(items)-(c-len(chosen)); i++ {
+			chosen = append(chosen, items[i])
+			walk(i + 1)
+			chosen = chosen[:len(chosen)-1]
+		}
+	}
+	walk(0)
+}
+
+// CountSums returns how many sets of k distinct entries of nums add up to
+// target; the same number AllSums would return combinations. It only works
+// through the distinct values, and multiplies out the ways of picking each
+// value's positions rather than listing them, so it copes with lists far too
+// ambiguous to list every combination of.
+func CountSums(nums []int, k, target int) int {
+	if k < 1 || k > len(nums) {
+		return 0
+	}
+
+	groups := groupValues(nums)
+	total := 0
+
+	eachMultiset(groups, k, target, func(counts []int) {
+		ways := 1
+		for g, c := range counts {
+			ways *= binomial(len(groups[g].positions), c)
+		}
+		total += ways
+	})
+
+	return total
+}
+
+// binomial returns n choose k.
+func binomial(n, k int) int {
+	if k < 0 || k > n {
+		return 0
+	}
+	if k > n-k {
+		k = n - k
+	}
+
+	result := 1
+	for i := 1; i <= k; i++ {
+		result = result * (n - k + i) / i
+	}
+	return result
+}
@@ -0,0 +1,181 @@
+package day01
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// bruteForce reports whether any k distinct positions of nums sum to target.
+func bruteForce(nums []int, k, target int) bool {
+	var try func(start, k, target int) bool
+	try = func(start, k, target int) bool {
+		if k == 0 {
+			return target == 0
+		}
+		for i := start; i < len(nums); i++ {
+			if try(i+1, k-1, target-nums[i]) {
+				return true
+			}
+		}
+		return false
+	}
+	return try(0, k, target)
+}
+
+func checkFound(t *testing.T, nums []int, k, target int, found []int) {
+	t.Helper()
+
+	if len(found) != k {
+		t.Fatalf("FindSum(%v, %d, %d) = %v, want %d positions", nums, k, target, found, k)
+	}
+	sum := 0
+	for i, p := range found {
+		if i > 0 && p <= found[i-1] {
+			t.Fatalf("FindSum(%v, %d, %d) = %v, want distinct ascending positions", nums, k, target, found)
+		}
+		sum += nums[p]
+	}
+	if sum != target {
+		t.Fatalf("FindSum(%v, %d, %d) = %v, which sums to %d", nums, k, target, found, sum)
+	}
+}
+
+func TestFindSum(t *testing.T) {
+	example := []int{1721, 979, 366, 299, 675, 1456}
+
+	tests := []struct {
+		nums   []int
+		k      int
+		target int
+		ok     bool
+	}{
+		{example, 2, 2020, true},
+		{example, 3, 2020, true},
+		{example, 1, 366, true},
+		{example, 4, 2020, false},
+		{example, 7, 2020, false},
+		{example, 0, 0, false},
+		// An entry can't be paired with itself...
+		{[]int{1010, 5}, 2, 2020, false},
+		// ...but two entries with the same value are fine.
+		{[]int{1010, 5, 1010}, 2, 2020, true},
+		{[]int{-5, 10, 2025, 7}, 2, 2020, true},
+		{[]int{-5, 10, 2015, 7}, 3, 2020, true},
+	}
+
+	for _, tt := range tests {
+		found, ok := FindSum(tt.nums, tt.k, tt.target)
+		if ok != tt.ok {
+			t.Errorf("FindSum(%v, %d, %d) = %v, %t; want ok %t", tt.nums, tt.k, tt.target, found, ok, tt.ok)
+			continue
+		}
+		if ok {
+			checkFound(t, tt.nums, tt.k, tt.target, found)
+		}
+	}
+
+	if found, _ := FindSum(example, 2, 2020); Product(example, found) != 514579 {
+		t.Errorf("pair product = %d, want 514579", Product(example, found))
+	}
+	if found, _ := FindSum(example, 3, 2020); Product(example, found) != 241861950 {
+		t.Errorf("triple product = %d, want 241861950", Product(example, found))
+	}
+}
+
+func TestFindSumRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for round := 0; round < 500; round++ {
+		nums := make([]int, rng.Intn(12))
+		for i := range nums {
+			nums[i] = rng.Intn(41) - 20
+		}
+		k := rng.Intn(5) + 1
+		target := rng.Intn(41) - 20
+
+		found, ok := FindSum(nums, k, target)
+		if want := bruteForce(nums, k, target); ok != want {
+			t.Fatalf("FindSum(%v, %d, %d) = %v, %t; want ok %t", nums, k, target, found, ok, want)
+		}
+		if ok {
+			checkFound(t, nums, k, target, found)
+		}
+	}
+}
+
+// bruteForceAll lists every set of k distinct positions of nums summing to
+// target, in order.
+func bruteForceAll(nums []int, k, target int) [][]int {
+	var all [][]int
+	var picked []int
+
+	var try func(start, k, target int)
+	try = func(start, k, target int) {
+		if k == 0 {
+			if target == 0 {
+				all = append(all, append([]int(nil), picked...))
+			}
+			return
+		}
+		for i := start; i < len(nums); i++ {
+			picked = append(picked, i)
+			try(i+1, k-1, target-nums[i])
+			picked = picked[:len(picked)-1]
+		}
+	}
+	try(0, k, target)
+	return all
+}
+
+func TestAllSums(t *testing.T) {
+	got := AllSums([]int{1010, 1010, 1010, 5, 2015}, 2, 2020)
+	want := []Combination{
+		{Positions: []int{0, 1}, Values: []int{1010, 1010}},
+		{Positions: []int{0, 2}, Values: []int{1010, 1010}},
+		{Positions: []int{1, 2}, Values: []int{1010, 1010}},
+		{Positions: []int{3, 4}, Values: []int{5, 2015}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AllSums = %v, want %v", got, want)
+	}
+}
+
+func TestAllSumsRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+
+	for round := 0; round < 500; round++ {
+		nums := make([]int, rng.Intn(10))
+		for i := range nums {
+			nums[i] = rng.Intn(11) - 5
+		}
+		k := rng.Intn(4) + 1
+		target := rng.Intn(11) - 5
+
+		want := bruteForceAll(nums, k, target)
+		got := AllSums(nums, k, target)
+		if len(got) != len(want) {
+			t.Fatalf("AllSums(%v, %d, %d) found %d, want %d", nums, k, target, len(got), len(want))
+		}
+		for i := range got {
+			if !reflect.DeepEqual(got[i].Positions, want[i]) {
+				t.Fatalf("AllSums(%v, %d, %d)[%d] = %v, want %v", nums, k, target, i, got[i].Positions, want[i])
+			}
+		}
+		if n := CountSums(nums, k, target); n != len(want) {
+			t.Fatalf("CountSums(%v, %d, %d) = %d, want %d", nums, k, target, n, len(want))
+		}
+	}
+}
+
+func TestCountSumsLarge(t *testing.T) {
+	// 100,000 copies of 1010 make nearly five billion pairs, which would be
+	// hopeless to list.
+	nums := make([]int, 100000)
+	for i := range nums {
+		nums[i] = 1010
+	}
+	if got, want := CountSums(nums, 2, 2020), 100000*99999/2; got != want {
+		t.Errorf("CountSums = %d, want %d", got, want)
+	}
+}
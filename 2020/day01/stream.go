@@ -0,0 +1,93 @@
+package day01
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+)
+
+// A Match is a pair of entries in a stream that add up to the target.
+type Match struct {
+	Lines  [2]int   // 1-based line numbers, the earlier one first
+	Values [2]int64 // the entries on those lines
+}
+
+// StreamStats says how much of a stream StreamPairs got through.
+type StreamStats struct {
+	Lines   int // lines read
+	Matches int // matches reported
+	Evicted int // values forgotten to stay within the limit
+}
+
+// StreamPairs reads an expense report one line at a time and calls found for
+// every entry that adds up to target with an entry before it. Each entry is
+// paired with the earliest remembered line holding its complement, so an
+// entry is reported at most once however many times its complement appears.
+//
+// Only the distinct values seen so far are kept, with the line each was
+// first seen on. If limit is more than zero, at most limit values are kept,
+// and the oldest is forgotten to make room for a new one; a match with a
+// forgotten value is missed, which the Evicted count owns up to.
+//
+// Entries are 64-bit and may be negative. Reading stops at the first line
+// that isn't an integer, or the first error from found.
+func StreamPairs(r io.Reader, target int64, limit int, found func(Match) error) (StreamStats, error) {
+	var stats StreamStats
+
+	seen := make(map[int64]int)
+	// With a limit, the values in seen in the order they were added, as a
+	// ring starting at oldest.
+	var order []int64
+	oldest := 0
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		stats.Lines++
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+
+		n, err := strconv.ParseInt(line, 10, 64)
+		if err != nil {
+			return stats, aoc.Errorf(stats.Lines, 1, line, "not a 64-bit integer")
+		}
+
+		if want, ok := complement(target, n); ok {
+			if first, ok := seen[want]; ok {
+				stats.Matches++
+				if err := found(Match{Lines: [2]int{first, stats.Lines}, Values: [2]int64{want, n}}); err != nil {
+					return stats, err
+				}
+			}
+		}
+
+		if _, ok := seen[n]; ok {
+			continue
+		}
+		seen[n] = stats.Lines
+		if limit <= 0 {
+			continue
+		}
+		if len(order) < limit {
+			order = append(order, n)
+			continue
+		}
+		delete(seen, order[oldest])
+		stats.Evicted++
+		order[oldest] = n
+		oldest = (oldest + 1) % limit
+	}
+
+	return stats, scanner.Err()
+}
+
+// complement returns the value that adds up to target with n, or false if it
+// doesn't fit in 64 bits.
+func complement(target, n int64) (int64, bool) {
+	c := target - n
+	if (n > 0 && c > target) || (n < 0 && c < target) {
+		return 0, false
+	}
+	return c, true
+}
@@ -0,0 +1,148 @@
+package day01
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+)
+
+func streamAll(t *testing.T, text string, target int64, limit int) ([]Match, StreamStats) {
+	t.Helper()
+
+	var matches []Match
+	stats, err := StreamPairs(strings.NewReader(text), target, limit, func(m Match) error {
+		matches = append(matches, m)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamPairs: %v", err)
+	}
+	return matches, stats
+}
+
+func TestStreamPairs(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		target int64
+		want   []Match
+	}{
+		{
+			name:   "example",
+			text:   "1721\n979\n366\n299\n675\n1456\n",
+			target: 2020,
+			want:   []Match{{Lines: [2]int{1, 4}, Values: [2]int64{1721, 299}}},
+		},
+		{
+			name:   "negative",
+			text:   "-5\r\n7\r\n2025\r\n",
+			target: 2020,
+			want:   []Match{{Lines: [2]int{1, 3}, Values: [2]int64{-5, 2025}}},
+		},
+		{
+			name:   "repeated complement",
+			text:   "1010\n1010\n1010\n",
+			target: 2020,
+			want: []Match{
+				{Lines: [2]int{1, 2}, Values: [2]int64{1010, 1010}},
+				{Lines: [2]int{1, 3}, Values: [2]int64{1010, 1010}},
+			},
+		},
+		{
+			name:   "64-bit",
+			text:   "9223372036854775807\n-9223372036854775808\n-1\n",
+			target: -1,
+			want: []Match{
+				{Lines: [2]int{1, 2}, Values: [2]int64{math.MaxInt64, math.MinInt64}},
+			},
+		},
+		{
+			name:   "complement overflows",
+			text:   "-9223372036854775808\n1\n",
+			target: math.MaxInt64,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, stats := streamAll(t, tt.text, tt.target, 0)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("matches = %v, want %v", got, tt.want)
+			}
+			if stats.Matches != len(tt.want) {
+				t.Errorf("stats.Matches = %d, want %d", stats.Matches, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestStreamPairsLimit(t *testing.T) {
+	// 10 is still there for 2010, but 1 is forgotten by the time 2019 comes
+	// along.
+	text := "1\n10\n20\n30\n2010\n2019\n"
+	got, stats := streamAll(t, text, 2020, 3)
+
+	want := []Match{{Lines: [2]int{2, 5}, Values: [2]int64{10, 2010}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matches = %v, want %v", got, want)
+	}
+	if want := (StreamStats{Lines: 6, Matches: 1, Evicted: 3}); stats != want {
+		t.Errorf("stats = %+v, want %+v", stats, want)
+	}
+}
+
+func TestStreamPairsRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+
+	for round := 0; round < 200; round++ {
+		nums := make([]int, rng.Intn(30))
+		lines := make([]string, len(nums))
+		for i := range nums {
+			nums[i] = rng.Intn(41) - 20
+			lines[i] = strconv.Itoa(nums[i])
+		}
+		target := rng.Intn(41) - 20
+
+		// Without a limit, every entry with a complement before it is
+		// reported, and there are matches exactly when FindSum finds a pair.
+		got, _ := streamAll(t, strings.Join(lines, "\n"), int64(target), 0)
+		want := 0
+		for i := range nums {
+			for j := 0; j < i; j++ {
+				if nums[i]+nums[j] == target {
+					want++
+					break
+				}
+			}
+		}
+		if len(got) != want {
+			t.Fatalf("StreamPairs(%v, %d) found %d matches, want %d", nums, target, len(got), want)
+		}
+		if _, ok := FindSum(nums, 2, target); ok != (want > 0) {
+			t.Fatalf("StreamPairs(%v, %d) disagrees with FindSum", nums, target)
+		}
+	}
+}
+
+func TestStreamPairsErrors(t *testing.T) {
+	_, err := StreamPairs(strings.NewReader("1\n2\n99999999999999999999\n"), 2020, 0, func(Match) error { return nil })
+	var pe *aoc.ParseError
+	if !errors.As(err, &pe) || pe.Line != 3 {
+		t.Errorf("StreamPairs with a huge number = %v, want a parse error on line 3", err)
+	}
+
+	stop := errors.New("stop")
+	stats, err := StreamPairs(strings.NewReader("1010\n1010\n1010\n"), 2020, 0, func(Match) error { return stop })
+	if err != stop {
+		t.Errorf("StreamPairs = %v, want the error from found", err)
+	}
+	if stats.Lines != 2 {
+		t.Errorf("StreamPairs read %d lines, want to stop after 2", stats.Lines)
+	}
+}
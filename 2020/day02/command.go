@@ -0,0 +1,71 @@
+package day02
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+	"github.com/tangledhelix/adventofcode2020/input"
+)
+
+func init() {
+	aoc.RegisterCommand(aoc.Command{
+		Name:    "passwords",
+		Usage:   "report [-policies list] [-format text|csv|json] [file]",
+		Summary: "show every password database entry's verdict under each policy, and why",
+		Run:     runPasswords,
+	})
+}
+
+func runPasswords(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: aoc passwords report [-policies list] [-format text|csv|json] [file]")
+	}
+
+	switch args[0] {
+	case "report":
+		return runReport(args[1:], stdout)
+	default:
+		return fmt.Errorf("unknown passwords command %q", args[0])
+	}
+}
+
+// runReport writes out the verdict on every entry, so a count that comes out
+// wrong can be tracked down to the entries responsible, and verdicts can be
+// diffed as policies change.
+func runReport(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("passwords report", flag.ContinueOnError)
+	names := policyList{"count", "positions"}
+	fs.Var(&names, "policies", "comma-separated policies to judge the entries by")
+	format := fs.String("format", "text", "output format: text, csv or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return errors.New("usage: aoc passwords report [-policies list] [-format text|csv|json] [file]")
+	}
+
+	var write func(io.Writer, []Judgement) error
+	switch *format {
+	case "text":
+		write = WriteText
+	case "csv":
+		write = WriteCSV
+	case "json":
+		write = WriteJSON
+	default:
+		return fmt.Errorf("unknown format %q, want text, csv or json", *format)
+	}
+
+	entries, err := input.ReadFile(fs.Arg(0), ReadDatabase)
+	if err != nil {
+		return err
+	}
+	judgements, err := Judge(entries, names)
+	if err != nil {
+		return err
+	}
+	return write(stdout, judgements)
+}
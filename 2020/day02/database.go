@@ -0,0 +1,128 @@
+package day02
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+	"github.com/tangledhelix/adventofcode2020/input"
+)
+
+// ReadDatabase reads a password database, one entry per line.
+func ReadDatabase(r io.Reader) ([]PasswordEntry, error) {
+	lines, err := input.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]PasswordEntry, len(lines))
+	for i, line := range lines {
+		entries[i], err = parseEntry(i+1, line)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, nil
+}
+
+// ParseEntry parses one line of a password database, like "2-3 b: bkkb",
+// which reads <low>-<high> <letter>: <password>.
+//
+// The letter may be more than one character, as long as it has no spaces or
+// colons in it. The password is everything after the ": ", colons and all,
+// but mustn't be empty or have spaces in it. The numbers count from 1, and
+// low mustn't be more than high.
+//
+// Errors are *aoc.ParseErrors pointing at the trouble, with no line number.
+func ParseEntry(line string) (PasswordEntry, error) {
+	return parseEntry(0, line)
+}
+
+func parseEntry(lineNum int, line string) (PasswordEntry, error) {
+	var e PasswordEntry
+	p := entryParser{lineNum: lineNum, line: line}
+
+	var err error
+	if e.Low, err = p.number("low"); err != nil {
+		return e, err
+	}
+	if err := p.expect("-", "'-' after the low number"); err != nil {
+		return e, err
+	}
+	if e.High, err = p.number("high"); err != nil {
+		return e, err
+	}
+	if err := p.expect(" ", "a space before the letter"); err != nil {
+		return e, err
+	}
+
+	end := strings.IndexFunc(line[p.pos:], func(c rune) bool { return c == ':' || unicode.IsSpace(c) })
+	if end < 0 {
+		end = len(line) - p.pos
+	}
+	e.Letter = line[p.pos : p.pos+end]
+	if e.Letter == "" {
+		return e, p.errorf(p.pos, "missing the letter")
+	}
+	p.pos += end
+
+	if err := p.expect(": ", "': ' between the letter and the password"); err != nil {
+		return e, err
+	}
+	e.Password = line[p.pos:]
+	if e.Password == "" {
+		return e, p.errorf(p.pos, "missing the password")
+	}
+	if i := strings.IndexFunc(e.Password, unicode.IsSpace); i >= 0 {
+		return e, p.errorf(p.pos+i, "the password can't have spaces in it")
+	}
+
+	if e.Low < 1 {
+		return e, p.errorf(0, "the numbers count from 1, not 0")
+	}
+	if e.Low > e.High {
+		return e, p.errorf(0, "low %d is more than high %d", e.Low, e.High)
+	}
+
+	return e, nil
+}
+
+// An entryParser walks along a line, keeping track of the byte offset it has
+// got to for errors.
+type entryParser struct {
+	lineNum int
+	line    string
+	pos     int
+}
+
+func (p *entryParser) number(what string) (int, error) {
+	start := p.pos
+	for p.pos < len(p.line) && p.line[p.pos] >= '0' && p.line[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos == start {
+		return 0, p.errorf(start, "expected the %s number", what)
+	}
+
+	n, err := strconv.Atoi(p.line[start:p.pos])
+	if err != nil {
+		return 0, p.errorf(start, "the %s number is too big", what)
+	}
+	return n, nil
+}
+
+func (p *entryParser) expect(s, what string) error {
+	if !strings.HasPrefix(p.line[p.pos:], s) {
+		return p.errorf(p.pos, "expected %s", what)
+	}
+	p.pos += len(s)
+	return nil
+}
+
+// errorf reports trouble at a byte offset in the line.
+func (p *entryParser) errorf(offset int, format string, args ...interface{}) error {
+	return aoc.Errorf(p.lineNum, offset+1, p.line, format, args...)
+}
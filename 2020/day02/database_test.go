@@ -0,0 +1,75 @@
+package day02
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+)
+
+func TestParseEntry(t *testing.T) {
+	tests := []struct {
+		line string
+		want PasswordEntry
+	}{
+		{"1-3 a: abcde", PasswordEntry{Low: 1, High: 3, Letter: "a", Password: "abcde"}},
+		{"12-16 z: zzzzzzzzzzzzzzzz", PasswordEntry{Low: 12, High: 16, Letter: "z", Password: "zzzzzzzzzzzzzzzz"}},
+		{"2-2 é: été", PasswordEntry{Low: 2, High: 2, Letter: "é", Password: "été"}},
+		{"1-4 ab: abxab", PasswordEntry{Low: 1, High: 4, Letter: "ab", Password: "abxab"}},
+		{"1-3 a: a:b:c", PasswordEntry{Low: 1, High: 3, Letter: "a", Password: "a:b:c"}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseEntry(tt.line)
+		if err != nil {
+			t.Errorf("ParseEntry(%q): %v", tt.line, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseEntry(%q) = %+v, want %+v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseEntryErrors(t *testing.T) {
+	tests := []struct {
+		line string
+		col  int
+		msg  string
+	}{
+		{"", 1, "expected the low number"},
+		{"-3 a: abc", 1, "expected the low number"},
+		{"1 a: abc", 2, "expected '-'"},
+		{"1- a: abc", 3, "expected the high number"},
+		{"1-3a: abc", 4, "expected a space"},
+		{"1-3 : abc", 5, "missing the letter"},
+		{"1-3 a abc", 6, "expected ': '"},
+		{"1-3 a:abc", 6, "expected ': '"},
+		{"1-3 a: ", 8, "missing the password"},
+		{"1-3 a: ab c", 10, "can't have spaces"},
+		{"0-3 a: abc", 1, "count from 1"},
+		{"3-1 a: abc", 1, "low 3 is more than high 1"},
+		{"99999999999999999999-1 a: abc", 1, "too big"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseEntry(tt.line)
+		var pe *aoc.ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("ParseEntry(%q) = %v, want a ParseError", tt.line, err)
+			continue
+		}
+		if pe.Col != tt.col || !strings.Contains(pe.Err.Error(), tt.msg) {
+			t.Errorf("ParseEntry(%q) = column %d %q, want column %d %q", tt.line, pe.Col, pe.Err, tt.col, tt.msg)
+		}
+	}
+}
+
+func TestReadDatabase(t *testing.T) {
+	_, err := ReadDatabase(strings.NewReader("1-3 a: abcde\r\n1-3 b cdefg\r\n"))
+	var pe *aoc.ParseError
+	if !errors.As(err, &pe) || pe.Line != 2 || pe.Col != 6 {
+		t.Errorf("ReadDatabase = %v, want an error at line 2, column 6", err)
+	}
+}
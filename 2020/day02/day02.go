@@ -57,21 +57,19 @@
  * How many passwords are valid according to the new interpretation of the policies?
  */
 
-package main
+package day02
 
 import (
+	"flag"
 	"fmt"
 	"io"
-	"os"
 	"strings"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
 )
 
-func check(e error) {
-	if e != nil {
-		if e != io.EOF {
-			panic(e)
-		}
-	}
+func init() {
+	aoc.Register(2020, 2, newSolver())
 }
 
 // use an empty interface, so we can pass a random series of
@@ -85,105 +83,73 @@ func debug(a ...interface{}) {
 	}
 }
 
-func main() {
+// The solver counts the passwords allowed by the policies each part names.
+type solver struct {
+	policy1 policyList
+	policy2 policyList
+}
 
-	// Read input file and break into lines
-	dat, err := os.ReadFile("input.txt")
-	check(err)
+func newSolver() *solver {
+	return &solver{
+		policy1: policyList{"count"},
+		policy2: policyList{"positions"},
+	}
+}
 
-	raw_data := strings.Split(string(dat), "\n")
+func (s *solver) Flags(fs *flag.FlagSet) {
+	var names []string
+	for _, name := range Policies() {
+		names = append(names, fmt.Sprintf("%s (%s)", name, PolicySummary(name)))
+	}
+	fs.Var(&s.policy1, "policy1", "day 2: comma-separated password policies a password must all pass in part 1; one of "+strings.Join(names, ", "))
+	fs.Var(&s.policy2, "policy2", "day 2: comma-separated password policies a password must all pass in part 2, like -policy1")
+}
 
+func (s *solver) Parse(input io.Reader) error {
+	_, err := ReadDatabase(input)
+	return err
+}
+
+// Count the passwords which pass the given policies.
+func countValid(entries []PasswordEntry, policy policyList) int {
 	// Keep track how many passwords are valid
 	validPasswordCount := 0
-
-	debug("looping over each line of file...")
-
-	for i := 0; i < len(raw_data); i++ {
-		// sample of input data:
-		// 2-3 b: bkkb
-		// <low_bound>-<high_bound> <letter>: <password>
-
-		var low, high int
-		var letter, password string
-
-		// We need to split left and right side because the %s consumes the :
-		// and therefore the fmt string doesn't match.
-		// This could also be solved using range or similar to iterate over
-		// the line character by character, but that's tedious.
-		chunks := strings.Split(string(raw_data[i]), ":")
-		debug("chunks:", chunks)
-
-		if len(chunks) == 2 {
-			debug("chunks[0]:", chunks[0])
-			debug("chunks[1]:", chunks[1])
-			_, err := fmt.Sscanf(chunks[0], "%d-%d %s", &low, &high, &letter)
-			check(err)
-			_, err = fmt.Sscanf(chunks[1], "%s", &password)
-			check(err)
-		}
-
-		// Determine how many times letter occurs in password
-		numberOccurrences := 0
-		for _, c := range password {
-			if string(c) == letter {
-				numberOccurrences++
-			}
-		}
-		debug("numberOccurrences=", numberOccurrences)
-
-		// If this number of occurrences is valid, increase the counter
-		// Ignore if the number of occurrences was 0
-		if numberOccurrences > 0 && numberOccurrences >= low && numberOccurrences <= high {
+	for _, e := range entries {
+		if policy.Valid(e) {
 			validPasswordCount++
-			debug(fmt.Sprintf("counting this as VALID (now %d)", validPasswordCount))
+			debug(fmt.Sprintf("counting %q as VALID (now %d)", e.Password, validPasswordCount))
 		}
 	}
 
-	// Print the final count
-	fmt.Println("Answer 1:", validPasswordCount)
-
-	// Now let's do this again with new rules for Part Two.
-	validPasswordCount = 0
-
-	for i := 0; i < len(raw_data); i++ {
-		var pos1, pos2 int
-		var letter, password string
-
-		// We need to split left and right side because the %s consumes the :
-		// and therefore the fmt string doesn't match.
-		// This could also be solved using range or similar to iterate over
-		// the line character by character, but that's tedious.
-		chunks := strings.Split(string(raw_data[i]), ":")
-		debug("chunks:", chunks)
-
-		if len(chunks) == 2 {
-			debug("chunks[0]:", chunks[0])
-			debug("chunks[1]:", chunks[1])
-			_, err := fmt.Sscanf(chunks[0], "%d-%d %s", &pos1, &pos2, &letter)
-			check(err)
-			_, err = fmt.Sscanf(chunks[1], "%s", &password)
-			check(err)
-		}
-
-		// check the two positions and make sure only ONE contains
-		// the letter. If neither or both do, then it fails.
-		numberOccurrences := 0
-		for n, c := range password {
-			if n+1 == pos1 && string(c) == letter {
-				numberOccurrences++
-			}
-			if n+1 == pos2 && string(c) == letter {
-				numberOccurrences++
-			}
-		}
+	// Return the final count
+	return validPasswordCount
+}
 
-		// If only ONE column contained the letter, good. Else bad.
-		if numberOccurrences == 1 {
-			validPasswordCount++
-		}
+// Part 1 counts the passwords that have the letter between low and high
+// times.
+func (s *solver) Part1(input io.Reader) (aoc.Answer, error) {
+	entries, err := ReadDatabase(input)
+	if err != nil {
+		return "", err
 	}
+	return aoc.Int(countValid(entries, s.policy1)), nil
+}
 
-	// Print the final (final) count
-	fmt.Println("Answer 2:", validPasswordCount)
+// Now let's do this again with new rules for Part Two: exactly one of the two
+// positions must contain the letter. If neither or both do, then it fails.
+func (s *solver) Part2(input io.Reader) (aoc.Answer, error) {
+	entries, err := ReadDatabase(input)
+	if err != nil {
+		return "", err
+	}
+	return aoc.Int(countValid(entries, s.policy2)), nil
+}
 
+// Solve parses the database once for both parts.
+func (s *solver) Solve(input io.Reader) (aoc.Answer, aoc.Answer, error) {
+	entries, err := ReadDatabase(input)
+	if err != nil {
+		return "", "", err
+	}
+	return aoc.Int(countValid(entries, s.policy1)), aoc.Int(countValid(entries, s.policy2)), nil
 }
@@ -0,0 +1,37 @@
+package day02
+
+import (
+	"testing"
+
+	"github.com/tangledhelix/adventofcode2020/aoc/aoctest"
+)
+
+func TestExamples(t *testing.T) {
+	aoctest.Run(t, newSolver(), []aoctest.Case{
+		{
+			Name:  "password list",
+			Input: "1-3 a: abcde\n1-3 b: cdefg\n2-9 c: ccccccccc\n",
+			Part1: "2",
+			Part2: "1",
+		},
+		{Name: "1-3 a: abcde", Input: "1-3 a: abcde\n", Part1: "1", Part2: "1"},
+		{Name: "1-3 b: cdefg", Input: "1-3 b: cdefg\n", Part1: "0", Part2: "0"},
+		{Name: "2-9 c: ccccccccc", Input: "2-9 c: ccccccccc\n", Part1: "1", Part2: "0"},
+	})
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, newSolver(), "536", "558")
+}
+
+func BenchmarkParse(b *testing.B) {
+	aoctest.BenchmarkParse(b, newSolver())
+}
+
+func BenchmarkPart1(b *testing.B) {
+	aoctest.BenchmarkPart(b, newSolver().Part1)
+}
+
+func BenchmarkPart2(b *testing.B) {
+	aoctest.BenchmarkPart(b, newSolver().Part2)
+}
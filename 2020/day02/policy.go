@@ -0,0 +1,202 @@
+package day02
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A PasswordEntry is one line of the password database: the policy numbers
+// and letter, and the password that was set under them.
+type PasswordEntry struct {
+	Low      int    `json:"low"`
+	High     int    `json:"high"`
+	Letter   string `json:"letter"`
+	Password string `json:"password"`
+}
+
+// String gives the entry back the way it reads in the database.
+func (e PasswordEntry) String() string {
+	return fmt.Sprintf("%d-%d %s: %s", e.Low, e.High, e.Letter, e.Password)
+}
+
+// A Policy is one reading of what the numbers and letter in a database entry
+// mean, and so of which passwords it allowed.
+type Policy interface {
+	Valid(e PasswordEntry) bool
+}
+
+// An Explainer is a Policy that can say why it allowed a password or not,
+// like "letter 'b' occurs 4 times, allowed 1-3". Reports use it; counting
+// doesn't need to.
+type Explainer interface {
+	Explain(e PasswordEntry) string
+}
+
+// PolicyFunc lets an ordinary function be used as a Policy.
+type PolicyFunc func(e PasswordEntry) bool
+
+func (f PolicyFunc) Valid(e PasswordEntry) bool {
+	return f(e)
+}
+
+// explainedPolicy is a Policy made of two functions, one to judge a password
+// and one to say why.
+type explainedPolicy struct {
+	valid   func(PasswordEntry) bool
+	explain func(PasswordEntry) string
+}
+
+func (p explainedPolicy) Valid(e PasswordEntry) bool     { return p.valid(e) }
+func (p explainedPolicy) Explain(e PasswordEntry) string { return p.explain(e) }
+
+type registeredPolicy struct {
+	summary string
+	policy  Policy
+}
+
+var policies = map[string]registeredPolicy{}
+
+func init() {
+	RegisterPolicy("count", "the letter occurs between low and high times",
+		explainedPolicy{letterCount, letterCountReason})
+	RegisterPolicy("positions", "the letter is at exactly one of positions low and high",
+		explainedPolicy{exactlyOnePosition, func(e PasswordEntry) string { return positionsReason(e) + ", needs exactly one" }})
+	RegisterPolicy("none", "the letter is at neither position low nor high",
+		explainedPolicy{noPosition, func(e PasswordEntry) string { return positionsReason(e) + ", needs neither" }})
+	RegisterPolicy("distinct", "the password has at least low distinct letters",
+		explainedPolicy{distinctLetters, distinctLettersReason})
+}
+
+// RegisterPolicy makes a policy available by name, to the -policy flags
+// among others. It panics if the name is already taken.
+func RegisterPolicy(name, summary string, p Policy) {
+	if _, dup := policies[name]; dup {
+		panic(fmt.Sprintf("day02: RegisterPolicy called twice for %q", name))
+	}
+	policies[name] = registeredPolicy{summary: summary, policy: p}
+}
+
+// LookupPolicy returns the policy registered under name.
+func LookupPolicy(name string) (Policy, bool) {
+	p, ok := policies[name]
+	return p.policy, ok
+}
+
+// Policies returns the names of every registered policy, sorted.
+func Policies() []string {
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PolicySummary returns the one line description a policy was registered
+// with.
+func PolicySummary(name string) string {
+	return policies[name].summary
+}
+
+// The sled rental place's policy, from part 1. A letter of more than one
+// character is counted where it doesn't overlap itself.
+func letterCount(e PasswordEntry) bool {
+	n := strings.Count(e.Password, e.Letter)
+	return n >= e.Low && n <= e.High
+}
+
+func letterCountReason(e PasswordEntry) string {
+	n := strings.Count(e.Password, e.Letter)
+	times := "times"
+	if n == 1 {
+		times = "time"
+	}
+	return fmt.Sprintf("letter '%s' occurs %d %s, allowed %d-%d", e.Letter, n, times, e.Low, e.High)
+}
+
+// The Official Toboggan Corporate Policy, from part 2.
+func exactlyOnePosition(e PasswordEntry) bool {
+	return letterAt(e, e.Low) != letterAt(e, e.High)
+}
+
+func noPosition(e PasswordEntry) bool {
+	return !letterAt(e, e.Low) && !letterAt(e, e.High)
+}
+
+// positionsReason says which of the entry's two positions hold its letter.
+func positionsReason(e PasswordEntry) string {
+	at1, at2 := letterAt(e, e.Low), letterAt(e, e.High)
+	switch {
+	case at1 && at2:
+		return fmt.Sprintf("letter '%s' at both positions %d and %d", e.Letter, e.Low, e.High)
+	case at1:
+		return fmt.Sprintf("letter '%s' at position %d but not %d", e.Letter, e.Low, e.High)
+	case at2:
+		return fmt.Sprintf("letter '%s' at position %d but not %d", e.Letter, e.High, e.Low)
+	default:
+		return fmt.Sprintf("letter '%s' at neither position %d nor %d", e.Letter, e.Low, e.High)
+	}
+}
+
+func distinctLetters(e PasswordEntry) bool {
+	return countDistinct(e.Password) >= e.Low
+}
+
+func distinctLettersReason(e PasswordEntry) string {
+	n := countDistinct(e.Password)
+	letters := "letters"
+	if n == 1 {
+		letters = "letter"
+	}
+	return fmt.Sprintf("%d distinct %s, needs at least %d", n, letters, e.Low)
+}
+
+func countDistinct(password string) int {
+	seen := map[rune]bool{}
+	for _, c := range password {
+		seen[c] = true
+	}
+	return len(seen)
+}
+
+// letterAt reports whether the entry's letter starts at the given position of
+// its password. Positions count characters from 1; Toboggan Corporate
+// Policies have no concept of "index zero".
+func letterAt(e PasswordEntry, pos int) bool {
+	chars := []rune(e.Password)
+	if pos < 1 || pos > len(chars) {
+		return false
+	}
+	return strings.HasPrefix(string(chars[pos-1:]), e.Letter)
+}
+
+// A policyList is a flag value naming one or more policies, separated by
+// commas, which a password must all pass.
+type policyList []string
+
+func (l *policyList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *policyList) Set(value string) error {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if _, ok := policies[name]; !ok {
+			return fmt.Errorf("unknown policy %q (have %s)", name, strings.Join(Policies(), ", "))
+		}
+		names = append(names, name)
+	}
+	*l = names
+	return nil
+}
+
+// Valid reports whether a password passes every policy in the list.
+func (l policyList) Valid(e PasswordEntry) bool {
+	for _, name := range l {
+		if !policies[name].policy.Valid(e) {
+			return false
+		}
+	}
+	return true
+}
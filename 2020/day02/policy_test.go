@@ -0,0 +1,81 @@
+package day02
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestPolicies(t *testing.T) {
+	tests := []struct {
+		entry PasswordEntry
+		want  map[string]bool
+	}{
+		{
+			entry: PasswordEntry{Low: 1, High: 3, Letter: "a", Password: "abcde"},
+			want:  map[string]bool{"count": true, "positions": true, "none": false, "distinct": true},
+		},
+		{
+			entry: PasswordEntry{Low: 1, High: 3, Letter: "b", Password: "cdefg"},
+			want:  map[string]bool{"count": false, "positions": false, "none": true, "distinct": true},
+		},
+		{
+			entry: PasswordEntry{Low: 2, High: 9, Letter: "c", Password: "ccccccccc"},
+			want:  map[string]bool{"count": true, "positions": false, "none": false, "distinct": false},
+		},
+		{
+			// Position 7 is past the end of the password, so it doesn't
+			// hold the letter.
+			entry: PasswordEntry{Low: 1, High: 7, Letter: "é", Password: "éte"},
+			want:  map[string]bool{"count": true, "positions": true, "none": false, "distinct": true},
+		},
+	}
+
+	for _, tt := range tests {
+		for name, want := range tt.want {
+			p, ok := LookupPolicy(name)
+			if !ok {
+				t.Fatalf("LookupPolicy(%q) found nothing", name)
+			}
+			if got := p.Valid(tt.entry); got != want {
+				t.Errorf("%s.Valid(%+v) = %v, want %v", name, tt.entry, got, want)
+			}
+			if _, ok := p.(Explainer); !ok {
+				t.Errorf("%s can't explain itself", name)
+			}
+		}
+	}
+}
+
+func TestPolicyFlags(t *testing.T) {
+	s := newSolver()
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	s.Flags(fs)
+
+	if err := fs.Parse([]string{"-policy1", "none,distinct"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Part1(strings.NewReader("1-3 a: abcde\n1-3 b: cdefg\n2-9 c: ccccccccc\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "1" {
+		t.Errorf("Part1 with -policy1 none,distinct = %s, want 1", got)
+	}
+
+	fs = flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(new(strings.Builder))
+	newSolver().Flags(fs)
+	if err := fs.Parse([]string{"-policy2", "count,nonsense"}); err == nil || !strings.Contains(err.Error(), `unknown policy "nonsense"`) {
+		t.Errorf("-policy2 count,nonsense: got error %v, want unknown policy", err)
+	}
+}
+
+func TestRegisterPolicyTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("registering count again didn't panic")
+		}
+	}()
+	RegisterPolicy("count", "again", PolicyFunc(letterCount))
+}
@@ -0,0 +1,125 @@
+package day02
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+// A Verdict is what one policy made of one database entry.
+type Verdict struct {
+	Policy string `json:"policy"`
+	Valid  bool   `json:"valid"`
+	Reason string `json:"reason"`
+}
+
+// A Judgement is a database entry with every policy's verdict on it.
+type Judgement struct {
+	Line     int           `json:"line"`
+	Entry    PasswordEntry `json:"entry"`
+	Verdicts []Verdict     `json:"verdicts"`
+}
+
+// Judge checks every entry of a database, which is assumed to start on line 1
+// with an entry on every line, against the named policies in turn. Verdicts
+// from policies that aren't Explainers have no reason.
+func Judge(entries []PasswordEntry, names []string) ([]Judgement, error) {
+	var checks []Policy
+	for _, name := range names {
+		p, ok := LookupPolicy(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown policy %q", name)
+		}
+		checks = append(checks, p)
+	}
+
+	judgements := make([]Judgement, len(entries))
+	for i, e := range entries {
+		j := Judgement{Line: i + 1, Entry: e, Verdicts: make([]Verdict, len(checks))}
+		for k, p := range checks {
+			v := Verdict{Policy: names[k], Valid: p.Valid(e)}
+			if x, ok := p.(Explainer); ok {
+				v.Reason = x.Explain(e)
+			}
+			j.Verdicts[k] = v
+		}
+		judgements[i] = j
+	}
+
+	return judgements, nil
+}
+
+func verdictWord(valid bool) string {
+	if valid {
+		return "valid"
+	}
+	return "invalid"
+}
+
+// WriteText writes a table with a row for every verdict, followed by how
+// many entries each policy found valid.
+func WriteText(w io.Writer, judgements []Judgement) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "line\tentry\tpolicy\tverdict\treason")
+
+	// Every entry has a verdict from the same policies, in the same order.
+	var valid []int
+	if len(judgements) > 0 {
+		valid = make([]int, len(judgements[0].Verdicts))
+	}
+	for _, j := range judgements {
+		for k, v := range j.Verdicts {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", j.Line, j.Entry, v.Policy, verdictWord(v.Valid), v.Reason)
+			if v.Valid {
+				valid[k]++
+			}
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for k, n := range valid {
+		if _, err := fmt.Fprintf(w, "%s: %d of %d valid\n", judgements[0].Verdicts[k].Policy, n, len(judgements)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes a header and then a record for every verdict, with the
+// entry's fields in columns of their own.
+func WriteCSV(w io.Writer, judgements []Judgement) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"line", "low", "high", "letter", "password", "policy", "valid", "reason"})
+	for _, j := range judgements {
+		for _, v := range j.Verdicts {
+			cw.Write([]string{
+				strconv.Itoa(j.Line),
+				strconv.Itoa(j.Entry.Low),
+				strconv.Itoa(j.Entry.High),
+				j.Entry.Letter,
+				j.Entry.Password,
+				v.Policy,
+				strconv.FormatBool(v.Valid),
+				v.Reason,
+			})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes a JSON object for every entry, one per line.
+func WriteJSON(w io.Writer, judgements []Judgement) error {
+	enc := json.NewEncoder(w)
+	for _, j := range judgements {
+		if err := enc.Encode(j); err != nil {
+			return err
+		}
+	}
+	return nil
+}
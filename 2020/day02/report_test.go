@@ -0,0 +1,101 @@
+package day02
+
+import (
+	"strings"
+	"testing"
+)
+
+var reportEntries = []PasswordEntry{
+	{Low: 1, High: 3, Letter: "a", Password: "abcde"},
+	{Low: 1, High: 3, Letter: "b", Password: "bbbb"},
+}
+
+func TestJudge(t *testing.T) {
+	got, err := Judge(reportEntries, []string{"count", "positions"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Verdict{
+		{Policy: "count", Valid: false, Reason: "letter 'b' occurs 4 times, allowed 1-3"},
+		{Policy: "positions", Valid: false, Reason: "letter 'b' at both positions 1 and 3, needs exactly one"},
+	}
+	if len(got) != 2 || got[1].Line != 2 {
+		t.Fatalf("Judge = %+v, want two judgements with the second on line 2", got)
+	}
+	for i, v := range got[1].Verdicts {
+		if v != want[i] {
+			t.Errorf("verdict %d = %+v, want %+v", i, v, want[i])
+		}
+	}
+
+	if _, err := Judge(reportEntries, []string{"count", "bogus"}); err == nil {
+		t.Error("Judge with an unknown policy succeeded")
+	}
+}
+
+func TestJudgeUnexplained(t *testing.T) {
+	RegisterPolicy("short", "the password is at most high letters", PolicyFunc(func(e PasswordEntry) bool {
+		return len(e.Password) <= e.High
+	}))
+	defer delete(policies, "short")
+
+	got, err := Judge(reportEntries, []string{"short"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Verdict{Policy: "short", Valid: false}
+	if v := got[1].Verdicts[0]; v != want {
+		t.Errorf("verdict = %+v, want %+v", v, want)
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	judgements, err := Judge(reportEntries, []string{"count"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		write func(w *strings.Builder) error
+		want  string
+	}{
+		{
+			name:  "text",
+			write: func(w *strings.Builder) error { return WriteText(w, judgements) },
+			want: "" +
+				"line  entry         policy  verdict  reason\n" +
+				"1     1-3 a: abcde  count   valid    letter 'a' occurs 1 time, allowed 1-3\n" +
+				"2     1-3 b: bbbb   count   invalid  letter 'b' occurs 4 times, allowed 1-3\n" +
+				"count: 1 of 2 valid\n",
+		},
+		{
+			name:  "csv",
+			write: func(w *strings.Builder) error { return WriteCSV(w, judgements) },
+			want: "" +
+				"line,low,high,letter,password,policy,valid,reason\n" +
+				"1,1,3,a,abcde,count,true,\"letter 'a' occurs 1 time, allowed 1-3\"\n" +
+				"2,1,3,b,bbbb,count,false,\"letter 'b' occurs 4 times, allowed 1-3\"\n",
+		},
+		{
+			name:  "json",
+			write: func(w *strings.Builder) error { return WriteJSON(w, judgements) },
+			want: "" +
+				`{"line":1,"entry":{"low":1,"high":3,"letter":"a","password":"abcde"},"verdicts":[{"policy":"count","valid":true,"reason":"letter 'a' occurs 1 time, allowed 1-3"}]}` + "\n" +
+				`{"line":2,"entry":{"low":1,"high":3,"letter":"b","password":"bbbb"},"verdicts":[{"policy":"count","valid":false,"reason":"letter 'b' occurs 4 times, allowed 1-3"}]}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := tt.write(&b); err != nil {
+				t.Fatal(err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package day03
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+	"github.com/tangledhelix/adventofcode2020/input"
+)
+
+func init() {
+	aoc.RegisterCommand(aoc.Command{
+		Name:    "toboggan",
+		Usage:   "search [-max-right n] [-max-down n] [file] | render [-slope right,down] [-color when] [-png file] [file]",
+		Summary: "find the slopes down a tree map meeting the fewest and most trees, or draw one",
+		Run:     runToboggan,
+	})
+}
+
+func runToboggan(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: aoc toboggan search|render [flags] [file]")
+	}
+
+	switch args[0] {
+	case "search":
+		return runSearch(args[1:], stdout)
+	case "render":
+		return runRender(args[1:], stdout)
+	default:
+		return fmt.Errorf("unknown toboggan command %q", args[0])
+	}
+}
+
+// runSearch tries every slope within bounds, left and right, and reports the
+// best and worst.
+func runSearch(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("toboggan search", flag.ContinueOnError)
+	maxRight := fs.Int("max-right", 31, "furthest to go right, or left, on each step")
+	maxDown := fs.Int("max-down", 10, "furthest to go down on each step")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return errors.New("usage: aoc toboggan search [-max-right n] [-max-down n] [file]")
+	}
+	if *maxRight < 0 || *maxDown < 1 {
+		return errors.New("-max-right must be at least 0 and -max-down at least 1")
+	}
+
+	trees, err := input.ReadFile(fs.Arg(0), readTreeMap)
+	if err != nil {
+		return err
+	}
+
+	r := Search(trees, *maxRight, *maxDown)
+	fmt.Fprintf(stdout, "searched %d slopes, right %d to %d and down 1 to %d\n", r.Searched, -*maxRight, *maxRight, *maxDown)
+	fmt.Fprintf(stdout, "fewest trees: %d on %s\n", r.Fewest, joinSlopes(r.FewestSlopes))
+	fmt.Fprintf(stdout, "most trees: %d on %s\n", r.Most, joinSlopes(r.MostSlopes))
+	return nil
+}
+
+// runRender draws the path down one slope, on the terminal or into a PNG.
+func runRender(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("toboggan render", flag.ContinueOnError)
+	slope := Slope{3, 1}
+	fs.Func("slope", "slope to draw, as right,down (default 3,1)", func(value string) (err error) {
+		slope, err = ParseSlope(value)
+		return err
+	})
+	colors := fs.String("color", "auto", "colour the drawing: always, never, or auto for when writing to a terminal")
+	pngFile := fs.String("png", "", "draw into this PNG file instead of writing text")
+	scale := fs.Int("scale", 4, "pixels per square in the PNG")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return errors.New("usage: aoc toboggan render [-slope right,down] [-color when] [-png file] [file]")
+	}
+
+	var useColors bool
+	switch *colors {
+	case "always":
+		useColors = true
+	case "never":
+	case "auto":
+		useColors = isTerminal(stdout)
+	default:
+		return fmt.Errorf("-color is %q, want always, never or auto", *colors)
+	}
+
+	trees, err := input.ReadFile(fs.Arg(0), readTreeMap)
+	if err != nil {
+		return err
+	}
+	run := NewRun(trees, slope)
+
+	if *pngFile == "" {
+		return run.WriteText(stdout, useColors)
+	}
+
+	f, err := os.Create(*pngFile)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, run.Image(*scale)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// isTerminal reports whether w writes to a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func joinSlopes(slopes []Slope) string {
+	fields := make([]string, len(slopes))
+	for i, s := range slopes {
+		fields[i] = s.String()
+	}
+	return strings.Join(fields, " ")
+}
@@ -90,142 +90,141 @@
  * each of the listed slopes?
  */
 
-package main
+package day03
 
 import (
+	"flag"
 	"fmt"
-	"os"
-	"strings"
+	"io"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+	"github.com/tangledhelix/adventofcode2020/grid"
+	"github.com/tangledhelix/adventofcode2020/input"
 )
 
-func main() {
-	// This is a given because we know the input size
-	const rowsOfTrees = 323
-	const colsOfTrees = 31
-
-	var pathsToCheck = [5][2]int{
-		{1, 1},
-		{3, 1}, /* checked in round 1 already */
-		{5, 1},
-		{7, 1},
-		{1, 2}}
-
-	// Create a tree map using a matrix of bools.
-	// true: a tree, false: an empty square
-	var treeMap [rowsOfTrees][colsOfTrees]bool
-
-	// Read input data
-	dat, _ := os.ReadFile("input.txt")
-	raw_data := strings.Split(string(dat), "\n")
-
-	// Loop over input, parse file
-	for row := 0; row < len(raw_data); row++ {
-		for col, c := range raw_data[row] {
-			// Store this square's value
-			if string(c) == "." {
-				treeMap[row][col] = false
-			} else if string(c) == "#" {
-				treeMap[row][col] = true
-			} else if string(c) != "\n" {
-				// We can ignore newline, but if we see something else,
-				// that's very unexpected.
-				panic("Unexpected character" + string(c))
-			}
+func init() {
+	aoc.Register(2020, 3, newSolver())
+}
+
+// The tree map: true for a tree, false for an empty square. The pattern
+// repeats forever to the right (and, for that matter, the left).
+type treeMap = *grid.Grid[bool]
+
+// The solver checks the slopes from the puzzle in part 2, unless it's given
+// others.
+type solver struct {
+	slopes     slopeList
+	slopesFile string
+}
+
+func newSolver() *solver {
+	return &solver{slopes: pathsToCheck}
+}
+
+func (s *solver) Flags(fs *flag.FlagSet) {
+	fs.Var(&s.slopes, "slopes", "day 3: slopes to multiply the trees on in part 2, as right,down separated by spaces")
+	fs.StringVar(&s.slopesFile, "slopes-file", "", "day 3: file listing the slopes for part 2 one per line, instead of -slopes")
+}
+
+func (s *solver) Parse(input io.Reader) error {
+	_, err := readTreeMap(input)
+	return err
+}
+
+func readTreeMap(r io.Reader) (treeMap, error) {
+	// Read input data. The grid makes sure every row is the same width, or
+	// the repeats wouldn't line up.
+	trees, err := grid.Parse(r, grid.WrapX, func(c byte) (bool, error) {
+		// Store this square's value
+		switch c {
+		case '.':
+			return false, nil
+		case '#':
+			return true, nil
+		default:
+			// If we see something else, that's very unexpected.
+			return false, fmt.Errorf("unexpected character %q", c)
 		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if trees.Height() == 0 || trees.Width() == 0 {
+		return nil, aoc.Errorf(0, 0, "", "no map in input")
 	}
 
-	// Store our final answer
-	answer := 1
+	return trees, nil
+}
 
-	for path := 0; path < len(pathsToCheck); path++ {
-		// How many trees we have encountered so far
-		encounteredTrees := 0
-
-		// Our position in the grid right now, starting from upper left.
-		// This is standard grid coordinate notation, X is the COLUMN, Y is the ROW.
-		posX := 0
-		posY := 0
-
-		addToX := pathsToCheck[path][0]
-		addToY := pathsToCheck[path][1]
-
-		stillInTheWoods := true
-		// fmt.Println("Now checking", addToX, addToY)
-
-		// Go use "for" instead of "while"... it's weird, but let's go with it
-		for stillInTheWoods {
-			// For each move, we need to move X +3 and Y +1. Apparently our toboggan is
-			// a chess knight. Note that starting like this means we assume there is no
-			// tree at (0,0) - perhaps we should check that, but we aren't here.
-
-			posX += addToX
-			posY += addToY
-			// fmt.Printf("Now at (%d,%d)\n", posX, posY)
-
-			// The first thing to note is that posX, posY, which are meant to track the
-			// matrix treeMap, are zero-indexed. So we should always add 1 to their
-			// value when doing comparisions to the rows, cols numbers, so we are
-			// comparing apples to apples. But that's only when we do math to see if
-			// we've exceeded the boundary of the map - never do that if looking into
-			// the matrix data itself; the posX and posY are already using the proper
-			// values to access matrix data.
-			//
-			// We need to look at the column we are in, and find out if we've
-			// wrapped past the right edge and must back to the left (because it
-			// repeats infinitely to the right).
-			//
-			// Example 1:
-			// x=29 (adding 1 gives 30). Adding 3 gives 33. That's 2 more than colsOfTrees.
-			// Result: must wrap back (we want to get to col 2, or index 1)
-			// At this point the actual value of X should be 29+3 or 32.
-			// (We got the 33 figure by adding 1 during comparision)
-			// So to get to the desired index, deduct colsOfTrees from X: 32 - 31 = 1
-			//
-			// Example 2:
-			// x=20 (adding 1 gives 21). Adding 3 gives 24, which is less than colsOfTrees.
-			// Result: no change needed
-			//
-			// Example 3:
-			// x=27 (adding 1 gives 28). Adding 3 gives 31, which is equal to colsOfTrees.
-			// We've hit the right edge but not gone past it yet.
-			// Result: no change needed
-			//
-			// Expressed as code, we can therefore say:
-			//
-			// x += 3
-			// if x+1 > colsOfTrees {
-			// 		/* we have to do something now */
-			// }
-			//
-			// Next question is what to do about it? Given the above examples, the thing
-			// to do is subtract colsOfTrees from X to get the new index.
-
-			if posX+1 > colsOfTrees {
-				posX -= colsOfTrees
-				// fmt.Printf("Reset X to %d, now (%d,%d)\n", posX, posX, posY)
-			}
-
-			// Check is whether we are now past the bottom of the map, because then
-			// we are finished. If Y+1 > rowsOfTrees, then we're out of the woods
-			// already and we're done.
-
-			if posY+1 > rowsOfTrees {
-				// We are no longer in the woods!
-				// break
-				stillInTheWoods = false
-			} else {
-				// Is there a tree at this position?
-				// fmt.Printf("RAW - %t\n", treeMap[posY][posX])
-				if treeMap[posY][posX] {
-					encounteredTrees++
-					// fmt.Printf("Encountered a tree at (%d,%d)\n", posX, posY)
-				}
-			}
+// Count the trees we hit going from the top-left corner to the bottom of the
+// map, moving right addToX and down addToY on each step. A negative addToX
+// goes left; addToY must be at least 1.
+func countTrees(trees treeMap, addToX, addToY int) int {
+	// How many trees we have encountered so far
+	encounteredTrees := 0
+
+	// Our position in the grid right now, starting from upper left.
+	// This is standard grid coordinate notation, X is the COLUMN, Y is the ROW.
+	// Note that starting like this means we assume there is no tree at (0,0)
+	// - perhaps we should check that, but we aren't here.
+	pos := grid.Point{X: 0, Y: 0}
+	step := grid.Point{X: addToX, Y: addToY}
+
+	// Apparently our toboggan is a chess knight. We never need to work out
+	// when we've gone past the right edge, because the map wraps around
+	// horizontally however far we go; At only tells us we're off the map once
+	// we're out of the bottom of the woods.
+	for {
+		pos = pos.Add(step)
+
+		tree, stillInTheWoods := trees.At(pos)
+		if !stillInTheWoods {
+			break
+		}
+		if tree {
+			encounteredTrees++
 		}
-		fmt.Printf("(%d,%d) encountered %d trees.\n", addToX, addToY, encounteredTrees)
-		answer *= encounteredTrees
 	}
 
-	fmt.Println("Final answer:", answer)
+	return encounteredTrees
+}
+
+// Part 1 only checks the one slope, right 3 and down 1.
+func (s *solver) Part1(input io.Reader) (aoc.Answer, error) {
+	trees, err := readTreeMap(input)
+	if err != nil {
+		return "", err
+	}
+
+	return aoc.Int(countTrees(trees, 3, 1)), nil
+}
+
+// The slopes part 2 checks, from -slopes-file if it was given.
+func (s *solver) slopesToCheck() ([]Slope, error) {
+	if s.slopesFile == "" {
+		return s.slopes, nil
+	}
+	return input.ReadFile(s.slopesFile, ReadSlopes)
+}
+
+// Part 2 multiplies together the trees found on each of the slopes to check.
+func (s *solver) Part2(input io.Reader) (aoc.Answer, error) {
+	slopes, err := s.slopesToCheck()
+	if err != nil {
+		return "", err
+	}
+
+	trees, err := readTreeMap(input)
+	if err != nil {
+		return "", err
+	}
+
+	// Store our final answer
+	answer := 1
+
+	for _, slope := range slopes {
+		answer *= countTrees(trees, slope.Right, slope.Down)
+	}
 
+	return aoc.Int(answer), nil
 }
@@ -0,0 +1,70 @@
+package day03
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tangledhelix/adventofcode2020/aoc/aoctest"
+)
+
+const example = `..##.......
+#...#...#..
+.#....#..#.
+..#.#...#.#
+.#...##..#.
+..#.##.....
+.#.#.#....#
+.#........#
+#.##...#...
+#...##....#
+.#..#...#.#
+`
+
+func TestExamples(t *testing.T) {
+	aoctest.Run(t, newSolver(), []aoctest.Case{
+		{Name: "tree map", Input: example, Part1: "7", Part2: "336"},
+	})
+}
+
+func TestCountTrees(t *testing.T) {
+	trees, err := readTreeMap(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		right, down int
+		want        int
+	}{
+		{1, 1, 2},
+		{3, 1, 7},
+		{5, 1, 3},
+		{7, 1, 4},
+		{1, 2, 2},
+		// Steps wider than the map still wrap around to the right square.
+		{14, 1, 7},
+		{27, 1, 3},
+		{-8, 1, 7},
+	}
+	for _, tt := range tests {
+		if got := countTrees(trees, tt.right, tt.down); got != tt.want {
+			t.Errorf("right %d, down %d: got %d trees, want %d", tt.right, tt.down, got, tt.want)
+		}
+	}
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, newSolver(), "176", "5872458240")
+}
+
+func BenchmarkParse(b *testing.B) {
+	aoctest.BenchmarkParse(b, newSolver())
+}
+
+func BenchmarkPart1(b *testing.B) {
+	aoctest.BenchmarkPart(b, newSolver().Part1)
+}
+
+func BenchmarkPart2(b *testing.B) {
+	aoctest.BenchmarkPart(b, newSolver().Part2)
+}
@@ -0,0 +1,134 @@
+package day03
+
+import (
+	"bufio"
+	"image"
+	"image/color"
+	"io"
+
+	"github.com/tangledhelix/adventofcode2020/grid"
+)
+
+// A Run is the toboggan's trip down the tree map on one slope, for drawing.
+type Run struct {
+	trees treeMap
+	hits  map[grid.Point]bool // squares landed on, as far out as the path goes
+
+	// The columns to draw: the map itself, plus as many more copies of it to
+	// the left or right as it takes to show the whole path.
+	fromX, toX int
+}
+
+// NewRun follows a slope from the top-left corner to the bottom of the map.
+func NewRun(trees treeMap, slope Slope) *Run {
+	r := &Run{trees: trees, hits: map[grid.Point]bool{}}
+
+	minX, maxX := 0, trees.Width()-1
+	pos := grid.Point{X: 0, Y: 0}
+	for {
+		pos = pos.Add(grid.Point{X: slope.Right, Y: slope.Down})
+		if !trees.Contains(pos) {
+			break
+		}
+		r.hits[pos] = true
+		if pos.X < minX {
+			minX = pos.X
+		}
+		if pos.X > maxX {
+			maxX = pos.X
+		}
+	}
+
+	// Round out to whole copies of the map.
+	w := trees.Width()
+	r.fromX = -((-minX + w - 1) / w) * w
+	r.toX = (maxX/w+1)*w - 1
+
+	return r
+}
+
+// The kinds of square in a drawing.
+const (
+	squareClear = '.'
+	squareTree  = '#'
+	squareMiss  = 'O' // landed on a clear square
+	squareHit   = 'X' // landed on a tree
+)
+
+// square returns how the square at p is drawn.
+func (r *Run) square(p grid.Point) byte {
+	tree := r.trees.Get(p)
+	switch {
+	case r.hits[p] && tree:
+		return squareHit
+	case r.hits[p]:
+		return squareMiss
+	case tree:
+		return squareTree
+	default:
+		return squareClear
+	}
+}
+
+// Width returns how many columns the drawing takes.
+func (r *Run) Width() int {
+	return r.toX - r.fromX + 1
+}
+
+// ANSI colours for the terminal.
+var ansiColors = map[byte]string{
+	squareTree: "\x1b[32m",   // green
+	squareMiss: "\x1b[1;36m", // bold cyan
+	squareHit:  "\x1b[1;31m", // bold red
+}
+
+// WriteText draws the map as text, a line per row, with the path overlaid:
+// O where the toboggan landed on a clear square and X where it hit a tree.
+// With colors, squares are coloured with ANSI escape codes.
+func (r *Run) WriteText(w io.Writer, colors bool) error {
+	bw := bufio.NewWriter(w)
+	for y := 0; y < r.trees.Height(); y++ {
+		for x := r.fromX; x <= r.toX; x++ {
+			c := r.square(grid.Point{X: x, Y: y})
+			if code := ansiColors[c]; colors && code != "" {
+				bw.WriteString(code)
+				bw.WriteByte(c)
+				bw.WriteString("\x1b[0m")
+			} else {
+				bw.WriteByte(c)
+			}
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// Colours for the image.
+var imageColors = map[byte]color.RGBA{
+	squareClear: {0xf4, 0xf1, 0xe8, 0xff}, // snow
+	squareTree:  {0x2e, 0x7d, 0x32, 0xff}, // green
+	squareMiss:  {0x1e, 0x88, 0xe5, 0xff}, // blue
+	squareHit:   {0xd3, 0x2f, 0x2f, 0xff}, // red
+}
+
+// Image draws the map with the path overlaid as a picture, each square scale
+// pixels across.
+func (r *Run) Image(scale int) image.Image {
+	if scale < 1 {
+		scale = 1
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, r.Width()*scale, r.trees.Height()*scale))
+	for y := 0; y < r.trees.Height(); y++ {
+		for x := r.fromX; x <= r.toX; x++ {
+			c := imageColors[r.square(grid.Point{X: x, Y: y})]
+			px, py := (x-r.fromX)*scale, y*scale
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetRGBA(px+dx, py+dy, c)
+				}
+			}
+		}
+	}
+	return img
+}
@@ -0,0 +1,107 @@
+package day03
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	trees, err := readTreeMap(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		slope Slope
+		want  string
+	}{
+		{
+			// The path from the puzzle, which takes three copies of the map.
+			slope: Slope{3, 1},
+			want: `..##.........##.........##.......
+#..O#...#..#...#...#..#...#...#..
+.#....X..#..#....#..#..#....#..#.
+..#.#...#O#..#.#...#.#..#.#...#.#
+.#...##..#..X...##..#..#...##..#.
+..#.##.......#.X#.......#.##.....
+.#.#.#....#.#.#.#.O..#.#.#.#....#
+.#........#.#........X.#........#
+#.##...#...#.##...#...#.X#...#...
+#...##....##...##....##...#X....#
+.#..#...#.#.#..#...#.#.#..#...X.#
+`,
+		},
+		{
+			// Going left draws a copy of the map to the left.
+			slope: Slope{-1, 4},
+			want: `..##.........##.......
+#...#...#..#...#...#..
+.#....#..#..#....#..#.
+..#.#...#.#..#.#...#.#
+.#...##..#O.#...##..#.
+..#.##.......#.##.....
+.#.#.#....#.#.#.#....#
+.#........#.#........#
+#.##...#.O.#.##...#...
+#...##....##...##....#
+.#..#...#.#.#..#...#.#
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := NewRun(trees, tt.slope).WriteText(&b, false); err != nil {
+			t.Fatal(err)
+		}
+		if got := b.String(); got != tt.want {
+			t.Errorf("slope %v drew:\n%s\nwant:\n%s", tt.slope, got, tt.want)
+		}
+	}
+}
+
+func TestWriteTextColors(t *testing.T) {
+	trees, err := readTreeMap(strings.NewReader(".#\n#.\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	if err := NewRun(trees, Slope{1, 1}).WriteText(&b, true); err != nil {
+		t.Fatal(err)
+	}
+	want := ".\x1b[32m#\x1b[0m\n\x1b[32m#\x1b[0m\x1b[1;36mO\x1b[0m\n"
+	if got := b.String(); got != want {
+		t.Errorf("drew %q, want %q", got, want)
+	}
+}
+
+func TestImage(t *testing.T) {
+	trees, err := readTreeMap(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img := NewRun(trees, Slope{3, 1}).Image(2)
+	if got := img.Bounds().Size(); got.X != 66 || got.Y != 22 {
+		t.Fatalf("image is %v, want 66x22", got)
+	}
+
+	// The tree hit at (6,2) is red, all over its square.
+	for _, p := range [][2]int{{12, 4}, {13, 5}} {
+		r, g, b, _ := img.At(p[0], p[1]).RGBA()
+		if r>>8 != 0xd3 || g>>8 != 0x2f || b>>8 != 0x2f {
+			t.Errorf("pixel %v is %02x%02x%02x, want the red of a tree hit", p, r>>8, g>>8, b>>8)
+		}
+	}
+}
+
+func TestEmptyMap(t *testing.T) {
+	// Blank lines make rows with no squares, which would leave a run
+	// nothing to wrap around.
+	for _, in := range []string{"", "\n\n"} {
+		if _, err := readTreeMap(strings.NewReader(in)); err == nil {
+			t.Errorf("readTreeMap(%q) = nil error, want no map", in)
+		}
+	}
+}
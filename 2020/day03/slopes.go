@@ -0,0 +1,154 @@
+package day03
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+	"github.com/tangledhelix/adventofcode2020/input"
+)
+
+// A Slope is how far the toboggan moves on each step: Right columns across,
+// which may be negative to go left, and Down rows, which must be at least 1
+// or it would never get out of the woods.
+type Slope struct {
+	Right, Down int
+}
+
+// The slopes part 2 checks.
+var pathsToCheck = []Slope{
+	{1, 1},
+	{3, 1}, /* checked in round 1 already */
+	{5, 1},
+	{7, 1},
+	{1, 2},
+}
+
+// String writes a slope the way ParseSlope reads it.
+func (s Slope) String() string {
+	return fmt.Sprintf("%d,%d", s.Right, s.Down)
+}
+
+// ParseSlope reads a slope written as "right,down", like "3,1" or "-2,1".
+func ParseSlope(text string) (Slope, error) {
+	right, down, ok := strings.Cut(text, ",")
+	if !ok {
+		return Slope{}, fmt.Errorf("slope %q isn't right,down", text)
+	}
+
+	var s Slope
+	var err error
+	if s.Right, err = strconv.Atoi(strings.TrimSpace(right)); err != nil {
+		return Slope{}, fmt.Errorf("slope %q: bad right", text)
+	}
+	if s.Down, err = strconv.Atoi(strings.TrimSpace(down)); err != nil {
+		return Slope{}, fmt.Errorf("slope %q: bad down", text)
+	}
+	if s.Down < 1 {
+		return Slope{}, fmt.Errorf("slope %q: down must be at least 1", text)
+	}
+
+	return s, nil
+}
+
+// ParseSlopes reads a list of slopes separated by spaces, like
+// "1,1 3,1 1,2".
+func ParseSlopes(text string) ([]Slope, error) {
+	var slopes []Slope
+	for _, field := range strings.Fields(text) {
+		s, err := ParseSlope(field)
+		if err != nil {
+			return nil, err
+		}
+		slopes = append(slopes, s)
+	}
+	if len(slopes) == 0 {
+		return nil, fmt.Errorf("no slopes in %q", text)
+	}
+	return slopes, nil
+}
+
+// ReadSlopes reads a list of slopes, one per line. Blank lines and lines
+// starting with # are skipped.
+func ReadSlopes(r io.Reader) ([]Slope, error) {
+	lines, err := input.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var slopes []Slope
+	for i, line := range lines {
+		text := strings.TrimSpace(line)
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		s, err := ParseSlope(text)
+		if err != nil {
+			return nil, aoc.Errorf(i+1, 1, line, "%v", err)
+		}
+		slopes = append(slopes, s)
+	}
+	if len(slopes) == 0 {
+		return nil, aoc.Errorf(0, 0, "", "no slopes in file")
+	}
+	return slopes, nil
+}
+
+// A slopeList is a flag value holding a list of slopes.
+type slopeList []Slope
+
+func (l *slopeList) String() string {
+	fields := make([]string, len(*l))
+	for i, s := range *l {
+		fields[i] = s.String()
+	}
+	return strings.Join(fields, " ")
+}
+
+func (l *slopeList) Set(value string) error {
+	slopes, err := ParseSlopes(value)
+	if err != nil {
+		return err
+	}
+	*l = slopes
+	return nil
+}
+
+// A SearchResult says which slopes met the fewest and the most trees.
+type SearchResult struct {
+	Searched     int     // how many slopes were tried
+	Fewest, Most int     // trees met
+	FewestSlopes []Slope // every slope meeting Fewest trees
+	MostSlopes   []Slope // every slope meeting Most trees
+}
+
+// Search tries every slope going up to maxRight columns left or right and
+// 1 to maxDown rows down, and finds the ones meeting the fewest and the most
+// trees. Slopes are tried, and so listed in the result, by Down and then
+// Right.
+func Search(trees treeMap, maxRight, maxDown int) SearchResult {
+	var r SearchResult
+	for down := 1; down <= maxDown; down++ {
+		for right := -maxRight; right <= maxRight; right++ {
+			s := Slope{right, down}
+			n := countTrees(trees, right, down)
+
+			if r.Searched == 0 || n < r.Fewest {
+				r.Fewest, r.FewestSlopes = n, nil
+			}
+			if r.Searched == 0 || n > r.Most {
+				r.Most, r.MostSlopes = n, nil
+			}
+			if n == r.Fewest {
+				r.FewestSlopes = append(r.FewestSlopes, s)
+			}
+			if n == r.Most {
+				r.MostSlopes = append(r.MostSlopes, s)
+			}
+			r.Searched++
+		}
+	}
+	return r
+}
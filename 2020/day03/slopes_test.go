@@ -0,0 +1,118 @@
+package day03
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+)
+
+func TestParseSlopes(t *testing.T) {
+	got, err := ParseSlopes(" 1,1  -3,1 2,10 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Slope{{1, 1}, {-3, 1}, {2, 10}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSlopes = %v, want %v", got, want)
+	}
+
+	for _, bad := range []string{"", "3", "3,", "x,1", "3,0", "3,-1", "1,1 2"} {
+		if _, err := ParseSlopes(bad); err == nil {
+			t.Errorf("ParseSlopes(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestReadSlopes(t *testing.T) {
+	got, err := ReadSlopes(strings.NewReader("# the puzzle's\n1,1\n\n3,1\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Slope{{1, 1}, {3, 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadSlopes = %v, want %v", got, want)
+	}
+
+	_, err = ReadSlopes(strings.NewReader("1,1\n1;2\n"))
+	var pe *aoc.ParseError
+	if !errors.As(err, &pe) || pe.Line != 2 {
+		t.Errorf("ReadSlopes with a bad line 2 = %v, want a parse error on line 2", err)
+	}
+}
+
+func TestSlopeFlags(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "slopes.txt")
+	if err := os.WriteFile(file, []byte("3,1\n7,1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args []string
+		want aoc.Answer
+	}{
+		{nil, "336"},
+		{[]string{"-slopes", "3,1 -8,1"}, "49"},
+		{[]string{"-slopes-file", file}, "28"},
+	}
+	for _, tt := range tests {
+		s := newSolver()
+		fs := flag.NewFlagSet("run", flag.ContinueOnError)
+		s.Flags(fs)
+		if err := fs.Parse(tt.args); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := s.Part2(strings.NewReader(example))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Part2 with %q = %s, want %s", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	trees, err := readTreeMap(strings.NewReader(example))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := Search(trees, 11, 3)
+	if r.Searched != 23*3 {
+		t.Errorf("searched %d slopes, want %d", r.Searched, 23*3)
+	}
+
+	// Every slope reported meets the reported number of trees, and no slope
+	// does better or worse.
+	for _, s := range r.FewestSlopes {
+		if n := countTrees(trees, s.Right, s.Down); n != r.Fewest {
+			t.Errorf("fewest slope %v meets %d trees, want %d", s, n, r.Fewest)
+		}
+	}
+	for _, s := range r.MostSlopes {
+		if n := countTrees(trees, s.Right, s.Down); n != r.Most {
+			t.Errorf("most slope %v meets %d trees, want %d", s, n, r.Most)
+		}
+	}
+	fewest, most := 0, 0
+	for down := 1; down <= 3; down++ {
+		for right := -11; right <= 11; right++ {
+			switch n := countTrees(trees, right, down); {
+			case n == r.Fewest:
+				fewest++
+			case n == r.Most:
+				most++
+			case n < r.Fewest || n > r.Most:
+				t.Errorf("slope %d,%d meets %d trees, outside %d to %d", right, down, n, r.Fewest, r.Most)
+			}
+		}
+	}
+	if fewest != len(r.FewestSlopes) || most != len(r.MostSlopes) {
+		t.Errorf("found %d fewest and %d most slopes, want %d and %d", len(r.FewestSlopes), len(r.MostSlopes), fewest, most)
+	}
+}
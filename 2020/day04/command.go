@@ -0,0 +1,239 @@
+package day04
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+	"github.com/tangledhelix/adventofcode2020/input"
+)
+
+func init() {
+	aoc.RegisterCommand(aoc.Command{
+		Name:    "passports",
+		Usage:   "check [-schema file] [-explain] [file] | export [-format jsonl|csv|batch] [-only valid|invalid] [-missing field] [file] | validate [-workers n] [-show invalid|all|none] [file]",
+		Summary: "count or explain passport batch failures, export the records, or validate a huge batch",
+		Run:     runPassports,
+	})
+}
+
+func runPassports(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: aoc passports check [flags] [file]")
+	}
+
+	switch args[0] {
+	case "check":
+		return runCheck(args[1:], stdout)
+	case "export":
+		return runExport(args[1:], stdout)
+	case "validate":
+		return runValidate(args[1:], stdout)
+	default:
+		return fmt.Errorf("unknown passports command %q", args[0])
+	}
+}
+
+// runCheck validates a batch and says why the invalid passports failed.
+func runCheck(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("passports check", flag.ContinueOnError)
+	schemaFile := fs.String("schema", "", "JSON file with the passport fields and rules to check, instead of the 2020 rules")
+	explain := fs.Bool("explain", false, "print each passport with anything wrong with it, and what")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return errors.New("usage: aoc passports check [-schema file] [-explain] [file]")
+	}
+
+	schema := DefaultSchema()
+	if *schemaFile != "" {
+		var err error
+		if schema, err = ReadSchemaFile(*schemaFile); err != nil {
+			return err
+		}
+	}
+
+	batch, err := input.ReadFile(fs.Arg(0), ReadBatch)
+	if err != nil {
+		return err
+	}
+	records := batch.Records
+
+	findings := make([][]Finding, len(records))
+	invalid, explained := 0, 0
+	for i, p := range records {
+		findings[i] = schema.Validate(p)
+		if !passes(findings[i]) {
+			invalid++
+		}
+
+		if *explain && len(findings[i]) > 0 {
+			explained++
+			fmt.Fprintf(stdout, "passport %d, lines %d-%d: %s\n", i+1, p.FirstLine, p.LastLine, p)
+			for _, f := range findings[i] {
+				fmt.Fprintf(stdout, "\t%s\n", describe(f))
+			}
+		}
+	}
+	if explained > 0 {
+		fmt.Fprintln(stdout)
+	}
+
+	fmt.Fprintf(stdout, "%d passports, %d valid, %d invalid\n", len(records), len(records)-invalid, invalid)
+	reasons := Summarize(findings)
+	if len(reasons) == 0 {
+		return nil
+	}
+	tw := tabwriter.NewWriter(stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "field\trule\tpassports")
+	for _, r := range reasons {
+		fmt.Fprintf(tw, "%s\t%s\t%d\n", r.Field, r.Rule, r.Count)
+	}
+	return tw.Flush()
+}
+
+// runExport writes a batch's records out in another form, perhaps only some
+// of them.
+func runExport(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("passports export", flag.ContinueOnError)
+	schemaFile := fs.String("schema", "", "JSON file with the passport fields and rules to check, instead of the 2020 rules")
+	format := fs.String("format", "jsonl", "output format: jsonl, csv, or batch for a canonical batch file")
+	only := fs.String("only", "", "only export valid or invalid records")
+	missing := fs.String("missing", "", "only export records without this field, or with it empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return errors.New("usage: aoc passports export [-schema file] [-format jsonl|csv|batch] [-only valid|invalid] [-missing field] [file]")
+	}
+
+	schema := DefaultSchema()
+	if *schemaFile != "" {
+		var err error
+		if schema, err = ReadSchemaFile(*schemaFile); err != nil {
+			return err
+		}
+	}
+
+	var write func([]*Record) error
+	switch *format {
+	case "jsonl":
+		write = func(records []*Record) error { return WriteJSONLines(stdout, records, schema) }
+	case "csv":
+		write = func(records []*Record) error { return WriteCSV(stdout, records, schema) }
+	case "batch":
+		write = func(records []*Record) error { return WriteCanonical(stdout, records) }
+	default:
+		return fmt.Errorf("unknown format %q, want jsonl, csv or batch", *format)
+	}
+
+	var keep func(*Record) bool
+	switch *only {
+	case "":
+		keep = func(*Record) bool { return true }
+	case "valid":
+		keep = schema.Valid
+	case "invalid":
+		keep = func(r *Record) bool { return !schema.Valid(r) }
+	default:
+		return fmt.Errorf("-only is %q, want valid or invalid", *only)
+	}
+
+	batch, err := input.ReadFile(fs.Arg(0), ReadBatch)
+	if err != nil {
+		return err
+	}
+
+	var records []*Record
+	for _, r := range batch.Records {
+		if _, ok := given(r, *missing); *missing != "" && ok {
+			continue
+		}
+		if keep(r) {
+			records = append(records, r)
+		}
+	}
+	return write(records)
+}
+
+// runValidate streams a batch through a Pipeline, for batches too big for
+// check.
+func runValidate(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("passports validate", flag.ContinueOnError)
+	schemaFile := fs.String("schema", "", "JSON file with the passport fields and rules to check, instead of the 2020 rules")
+	workers := fs.Int("workers", runtime.NumCPU(), "number of records to validate at once")
+	show := fs.String("show", "invalid", "which records to print a verdict for: invalid, all or none")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return errors.New("usage: aoc passports validate [-schema file] [-workers n] [-show invalid|all|none] [file]")
+	}
+	if *show != "invalid" && *show != "all" && *show != "none" {
+		return fmt.Errorf("-show is %q, want invalid, all or none", *show)
+	}
+
+	p := &Pipeline{Schema: DefaultSchema(), Workers: *workers}
+	if *schemaFile != "" {
+		var err error
+		if p.Schema, err = ReadSchemaFile(*schemaFile); err != nil {
+			return err
+		}
+	}
+
+	name := fs.Arg(0)
+	r, err := input.Open(name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	w := bufio.NewWriter(stdout)
+	stats, err := p.Run(r, func(res Result) error {
+		valid := res.Valid()
+		if *show == "none" || (*show == "invalid" && valid) {
+			return nil
+		}
+		verdict := "valid"
+		if !valid {
+			verdict = "invalid"
+		}
+		if len(res.Findings) == 0 {
+			_, err := fmt.Fprintf(w, "lines %d-%d: %s\n", res.Record.FirstLine, res.Record.LastLine, verdict)
+			return err
+		}
+		problems := make([]string, len(res.Findings))
+		for i, f := range res.Findings {
+			problems[i] = describe(f)
+		}
+		_, err := fmt.Fprintf(w, "lines %d-%d: %s: %s\n", res.Record.FirstLine, res.Record.LastLine, verdict, strings.Join(problems, "; "))
+		return err
+	})
+	if err := input.Named(err, name); err != nil {
+		// Keep the verdicts already written.
+		w.Flush()
+		return err
+	}
+
+	fmt.Fprintf(w, "%d passports, %d valid, %d invalid\n", stats.Records, stats.Valid, stats.Records-stats.Valid)
+	fmt.Fprintf(w, "%d bytes in %v with %d workers: %.0f passports/s, %.1f MB/s\n",
+		stats.Bytes, stats.Elapsed.Round(time.Millisecond), stats.Workers, stats.RecordsPerSecond(), stats.BytesPerSecond()/1e6)
+	return w.Flush()
+}
+
+// describe writes a finding with the rule it broke, and whether it's only a
+// warning.
+func describe(f Finding) string {
+	if f.Warning {
+		return fmt.Sprintf("%s (%s, warning)", f, f.Rule)
+	}
+	return fmt.Sprintf("%s (%s)", f, f.Rule)
+}
@@ -136,222 +136,121 @@
 * passports are valid?
  */
 
-package main
+package day04
 
 import (
-	"fmt"
+	"flag"
 	"io"
-	"os"
-	"regexp"
-	"strings"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
 )
 
-// Data structures to represent and store passports. We don't need to store the
-// data, only the presence of the field, so we can use bool.
-type passport struct {
-	byr string /* birth year */
-	iyr string /* issue year */
-	eyr string /* expiration year */
-	hgt string /* height */
-	hcl string /* hair color */
-	ecl string /* eye color */
-	pid string /* passport id */
-	cid bool   /* country id - we ignore this, so don't store more than a bool */
+func init() {
+	aoc.Register(2020, 4, newSolver())
 }
-type passportDatabase [500]passport
 
-func check(e error) {
-	if e != nil {
-		if e != io.EOF {
-			panic(e)
-		}
-	}
-}
+// Data structures to represent and store passports: each one is a Record,
+// keeping its fields as written. What the fields must hold is up to the
+// Schema.
+type passportDatabase []*Record
 
-// Only check that required fields have a value; not what
-// the value is. There's another function for that.
-func checkRequiredFields(record passport) bool {
-	if len(record.byr) == 0 {
-		return false
-	}
-	if len(record.iyr) == 0 {
-		return false
-	}
-	if len(record.eyr) == 0 {
-		return false
-	}
-	if len(record.hgt) == 0 {
-		return false
-	}
-	if len(record.hcl) == 0 {
-		return false
-	}
-	if len(record.ecl) == 0 {
-		return false
-	}
-	if len(record.pid) == 0 {
-		return false
-	}
+// The solver checks passports against the puzzle's schema, or one loaded from
+// the file named by -schema.
+type solver struct {
+	schemaFile string
 
-	return true
+	schema     *Schema
+	loadedFrom string // the file schema was loaded from
 }
 
-func convertStringToNumber(s string) int {
-	var n int
-	fmt.Sscanf(s, "%d", &n)
-	return n
+func newSolver() *solver {
+	return &solver{}
 }
 
-func validatePassport(record passport) bool {
-	// Regex to check for 4-digit number
-	reYear := regexp.MustCompile(`^\d{4}$`)
+func (s *solver) Flags(fs *flag.FlagSet) {
+	fs.StringVar(&s.schemaFile, "schema", "", "day 4: JSON file with the passport fields and rules to check, instead of the 2020 rules")
+}
 
-	// byr (Birth Year) - four digits; at least 1920 and at most 2002.
-	match := reYear.MatchString(record.byr)
-	if match == false {
-		return false
+// rules returns the schema to check passports against, loading it if need
+// be.
+func (s *solver) rules() (*Schema, error) {
+	if s.schemaFile == "" {
+		return DefaultSchema(), nil
 	}
-	byr := convertStringToNumber(record.byr)
-	if byr < 1920 || byr > 2002 {
-		return false
+	if s.schema == nil || s.loadedFrom != s.schemaFile {
+		schema, err := ReadSchemaFile(s.schemaFile)
+		if err != nil {
+			return nil, err
+		}
+		s.schema, s.loadedFrom = schema, s.schemaFile
 	}
+	return s.schema, nil
+}
 
-	// iyr (Issue Year) - four digits; at least 2010 and at most 2020.
-	match = reYear.MatchString(record.iyr)
-	if match == false {
-		return false
-	}
-	iyr := convertStringToNumber(record.iyr)
-	if iyr < 2010 || iyr > 2020 {
-		return false
-	}
+func (s *solver) Parse(input io.Reader) error {
+	_, err := readPassports(input)
+	return err
+}
 
-	// eyr (Expiration Year) - four digits; at least 2020 and at most 2030.
-	match = reYear.MatchString(record.eyr)
-	if match == false {
-		return false
-	}
-	eyr := convertStringToNumber(record.eyr)
-	if eyr < 2020 || eyr > 2030 {
-		return false
+func readPassports(r io.Reader) (passportDatabase, error) {
+	// Read input file and break into records, which are separated by blank
+	// lines. A record can be spread over several lines.
+	batch, err := ReadBatch(r)
+	if err != nil {
+		return nil, err
 	}
 
-	// hgt (Height) - a number followed by either cm or in:
-	// If cm, the number must be at least 150 and at most 193.
-	// If in, the number must be at least 59 and at most 76.
-	reHeight := regexp.MustCompile(`^(?P<Value>\d+)(?P<Unit>cm|in)$`)
-	matches := reHeight.FindStringSubmatch(record.hgt)
-	if len(matches) == 3 {
-		measurement := convertStringToNumber(matches[1])
-		if matches[2] == "cm" {
-			if measurement < 150 || measurement > 193 {
-				return false
-			}
-		} else if matches[2] == "in" {
-			if measurement < 59 || measurement > 76 {
-				return false
+	// The puzzle's batches are all key:value pairs, so point at anything
+	// that isn't.
+	for _, record := range batch.Records {
+		for _, f := range record.Fields {
+			if f.Malformed {
+				return nil, aoc.Errorf(f.Line, f.Col, record.Line(f.Line), "expected key:value, got %q", f.Key)
 			}
 		}
-	} else {
-		return false
 	}
 
-	// hcl (Hair Color) - a # followed by exactly six characters 0-9 or a-f.
-	reHairColor := regexp.MustCompile(`^#[0-9a-f]{6}$`)
-	match = reHairColor.MatchString(record.hcl)
-	if match == false {
-		return false
-	}
+	return batch.Records, nil
+}
 
-	// ecl (Eye Color) - exactly one of: amb blu brn gry grn hzl oth.
-	reEyeColor := regexp.MustCompile(`^(amb|blu|brn|gry|grn|hzl|oth)$`)
-	match = reEyeColor.MatchString(record.ecl)
-	if match == false {
-		return false
+// "valid" here means only that it has all of the required fields
+func (s *solver) Part1(input io.Reader) (aoc.Answer, error) {
+	schema, err := s.rules()
+	if err != nil {
+		return "", err
 	}
-
-	// pid (Passport ID) - a nine-digit number, including leading zeroes.
-	rePassportId := regexp.MustCompile(`^\d{9}$`)
-	match = rePassportId.MatchString(record.pid)
-	if match == false {
-		return false
+	records, err := readPassports(input)
+	if err != nil {
+		return "", err
 	}
 
-	// cid (Country ID) - ignored, missing or not.
-
-	// Didn't return false yet? Good news, that means it's valid!
-	return true
-}
-
-func main() {
-
-	// A place to store the records we find in the data
-	var records passportDatabase
-
-	// Read input file and break into lines
-	dat, err := os.ReadFile("input.txt")
-	check(err)
-	rawData := strings.Split(string(dat), "\n")
-
-	// Keep track of the record number we're on. We have to do this manually
-	// because the file is not one-record-per-line and we have to know when
-	// to allocate a new passport entry to store our data to.
-	recordNum := 0
-
-	// Parse the file contents
-	for lineNum := 0; lineNum < len(rawData); lineNum++ {
-		line := string(rawData[lineNum])
-
-		// If we started a new record after a blank line, skip to the next cycle
-		// and increment the record number
-		if line == "" {
-			recordNum++
-			continue
+	validPassports := 0
+	for recordNum := 0; recordNum < len(records); recordNum++ {
+		if schema.Complete(records[recordNum]) {
+			validPassports++
 		}
+	}
 
-		// Split the key:val pairs into an array, look at each in turn
-		pairs := strings.Split(line, " ")
-		for pairNum := 0; pairNum < len(pairs); pairNum++ {
-			// Split the key:val into an array to isolate the key
-			words := strings.Split(pairs[pairNum], ":")
+	return aoc.Int(validPassports), nil
+}
 
-			// Store true for any field that we found in this record.
-			switch words[0] {
-			case "byr":
-				records[recordNum].byr = words[1]
-			case "iyr":
-				records[recordNum].iyr = words[1]
-			case "eyr":
-				records[recordNum].eyr = words[1]
-			case "hgt":
-				records[recordNum].hgt = words[1]
-			case "hcl":
-				records[recordNum].hcl = words[1]
-			case "ecl":
-				records[recordNum].ecl = words[1]
-			case "pid":
-				records[recordNum].pid = words[1]
-			case "cid":
-				records[recordNum].cid = true
-			}
-		}
+// "validated" means that the data is also good. Not the same thing!
+func (s *solver) Part2(input io.Reader) (aoc.Answer, error) {
+	schema, err := s.rules()
+	if err != nil {
+		return "", err
+	}
+	records, err := readPassports(input)
+	if err != nil {
+		return "", err
 	}
 
-	// Now that the file has been parsed, let's evaluate each record for validity.
-	// "valid" here means only that it has all of the required fields
-	// "validated" means that the data is also good. Not the same thing!
-	validPassports := 0
 	validatedPassports := 0
-
 	for recordNum := 0; recordNum < len(records); recordNum++ {
-		if checkRequiredFields(records[recordNum]) {
-			validPassports++
-		}
-		if validatePassport(records[recordNum]) {
+		if schema.Valid(records[recordNum]) {
 			validatedPassports++
 		}
 	}
 
-	fmt.Println("all fields present:", validPassports)
-	fmt.Println("data validated:", validatedPassports)
+	return aoc.Int(validatedPassports), nil
 }
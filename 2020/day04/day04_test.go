@@ -0,0 +1,120 @@
+package day04
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tangledhelix/adventofcode2020/aoc/aoctest"
+)
+
+const example = `ecl:gry pid:860033327 eyr:2020 hcl:#fffffd
+byr:1937 iyr:2017 cid:147 hgt:183cm
+
+iyr:2013 ecl:amb cid:350 eyr:2023 pid:028048884
+hcl:#cfa07d byr:1929
+
+hcl:#ae17e1 iyr:2013
+eyr:2024
+ecl:brn pid:760753108 byr:1931
+hgt:179cm
+
+hcl:#cfa07d eyr:2025 pid:166559648
+iyr:2011 ecl:brn hgt:59in
+`
+
+const invalidPassports = `eyr:1972 cid:100
+hcl:#18171d ecl:amb hgt:170 pid:186cm iyr:2018 byr:1926
+
+iyr:2019
+hcl:#602927 eyr:1967 hgt:170cm
+ecl:grn pid:012533040 byr:1946
+
+hcl:dab227 iyr:2012
+ecl:brn hgt:182cm pid:021572410 eyr:2020 byr:1992 cid:277
+
+hgt:59cm ecl:zzz
+eyr:2038 hcl:74454a iyr:2023
+pid:3556412378 byr:2007
+`
+
+const validPassports = `pid:087499704 hgt:74in ecl:grn iyr:2012 eyr:2030 byr:1980
+hcl:#623a2f
+
+eyr:2029 ecl:blu cid:129 byr:1989
+iyr:2014 pid:896056539 hcl:#a97842 hgt:165cm
+
+hcl:#888785
+hgt:164cm byr:2001 iyr:2015 cid:88
+pid:545766238 ecl:hzl
+eyr:2022
+
+iyr:2010 hgt:158cm hcl:#b6652a ecl:blu byr:1944 eyr:2021 pid:093154719
+`
+
+func TestExamples(t *testing.T) {
+	aoctest.Run(t, newSolver(), []aoctest.Case{
+		{Name: "required fields", Input: example, Part1: "2"},
+		{Name: "no final newline", Input: strings.TrimSuffix(example, "\n"), Part1: "2"},
+		{Name: "invalid passports", Input: invalidPassports, Part1: "4", Part2: "0"},
+		{Name: "valid passports", Input: validPassports, Part1: "4", Part2: "4"},
+	})
+}
+
+// The example field values from part two, each checked by swapping it into an
+// otherwise valid passport.
+func TestFieldExamples(t *testing.T) {
+	valid := map[string]string{
+		"byr": "1980", "iyr": "2012", "eyr": "2030", "hgt": "74in",
+		"hcl": "#623a2f", "ecl": "grn", "pid": "087499704",
+	}
+
+	tests := []struct {
+		field string
+		value string
+		valid bool
+	}{
+		{"byr", "2002", true},
+		{"byr", "2003", false},
+		{"hgt", "60in", true},
+		{"hgt", "190cm", true},
+		{"hgt", "190in", false},
+		{"hgt", "190", false},
+		{"hcl", "#123abc", true},
+		{"hcl", "#123abz", false},
+		{"hcl", "123abc", false},
+		{"ecl", "brn", true},
+		{"ecl", "wat", false},
+		{"pid", "000000001", true},
+		{"pid", "0123456789", false},
+	}
+
+	for _, tt := range tests {
+		record := &Record{}
+		for k, v := range valid {
+			if k == tt.field {
+				v = tt.value
+			}
+			record.Fields = append(record.Fields, Field{Key: k, Value: v})
+		}
+
+		if got := DefaultSchema().Valid(record); got != tt.valid {
+			t.Errorf("%s:%s valid = %t, want %t", tt.field, tt.value, got, tt.valid)
+		}
+	}
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, newSolver(), "208", "167")
+}
+
+func BenchmarkParse(b *testing.B) {
+	aoctest.BenchmarkParse(b, newSolver())
+}
+
+func BenchmarkPart1(b *testing.B) {
+	aoctest.BenchmarkPart(b, newSolver().Part1)
+}
+
+func BenchmarkPart2(b *testing.B) {
+	aoctest.BenchmarkPart(b, newSolver().Part2)
+}
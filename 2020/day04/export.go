@@ -0,0 +1,111 @@
+package day04
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// exportedRecord is how a record is written as JSON.
+type exportedRecord struct {
+	FirstLine int             `json:"first_line"`
+	LastLine  int             `json:"last_line"`
+	Valid     bool            `json:"valid"`
+	Fields    []exportedField `json:"fields"`
+}
+
+type exportedField struct {
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+	Malformed bool   `json:"malformed,omitempty"`
+}
+
+// WriteJSONLines writes a JSON object for each record, one per line, with its
+// fields in order, and whether the schema finds it valid.
+func WriteJSONLines(w io.Writer, records []*Record, schema *Schema) error {
+	enc := json.NewEncoder(w)
+	for _, r := range records {
+		out := exportedRecord{
+			FirstLine: r.FirstLine,
+			LastLine:  r.LastLine,
+			Valid:     schema.Valid(r),
+			Fields:    make([]exportedField, len(r.Fields)),
+		}
+		for i, f := range r.Fields {
+			out.Fields[i] = exportedField{Key: f.Key, Value: f.Value, Malformed: f.Malformed}
+		}
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes a header and then a row for each record, with a column for
+// each field in the schema, in the schema's order. Anything else in a record,
+// like unknown or repeated fields, goes in a last column named "other", as
+// it would be written in a batch.
+func WriteCSV(w io.Writer, records []*Record, schema *Schema) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"first_line"}
+	column := map[string]int{}
+	for _, f := range schema.Fields {
+		column[f.Name] = len(header)
+		header = append(header, f.Name)
+	}
+	header = append(header, "other", "valid")
+	cw.Write(header)
+
+	for _, r := range records {
+		row := make([]string, len(header))
+		row[0] = strconv.Itoa(r.FirstLine)
+
+		// A field can be given with an empty value, so an empty column
+		// doesn't mean it's free.
+		filled := make([]bool, len(header))
+		var other []string
+		for _, f := range r.Fields {
+			i, known := column[f.Key]
+			if !known || f.Malformed || filled[i] {
+				other = append(other, f.String())
+				continue
+			}
+			row[i], filled[i] = f.Value, true
+		}
+		row[len(row)-2] = strings.Join(other, " ")
+		row[len(row)-1] = strconv.FormatBool(schema.Valid(r))
+
+		cw.Write(row)
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteCanonical writes the records as a batch in a standard form: each
+// record on one line with its fields sorted by key, and a blank line between
+// records. Repeated keys keep the order they were written in.
+func WriteCanonical(w io.Writer, records []*Record) error {
+	for i, r := range records {
+		fields := append([]Field(nil), r.Fields...)
+		sort.SliceStable(fields, func(a, b int) bool { return fields[a].Key < fields[b].Key })
+
+		pairs := make([]string, len(fields))
+		for j, f := range fields {
+			pairs[j] = f.String()
+		}
+
+		line := strings.Join(pairs, " ") + "\n"
+		if i > 0 {
+			line = "\n" + line
+		}
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
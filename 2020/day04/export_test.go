@@ -0,0 +1,94 @@
+package day04
+
+import (
+	"strings"
+	"testing"
+)
+
+const exportBatch = `pid:087499704 hgt:74in ecl:grn iyr:2012
+eyr:2030 byr:1980 hcl:#623a2f
+
+hgt:59cm pid:1 foo:bar pid:2
+`
+
+func readExportBatch(t *testing.T) []*Record {
+	t.Helper()
+
+	b, err := ReadBatch(strings.NewReader(exportBatch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b.Records
+}
+
+func TestWriteJSONLines(t *testing.T) {
+	var b strings.Builder
+	if err := WriteJSONLines(&b, readExportBatch(t)[1:], DefaultSchema()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"first_line":4,"last_line":4,"valid":false,"fields":[{"key":"hgt","value":"59cm"},{"key":"pid","value":"1"},{"key":"foo","value":"bar"},{"key":"pid","value":"2"}]}` + "\n"
+	if got := b.String(); got != want {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var b strings.Builder
+	if err := WriteCSV(&b, readExportBatch(t), DefaultSchema()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "" +
+		"first_line,byr,iyr,eyr,hgt,hcl,ecl,pid,cid,other,valid\n" +
+		"1,1980,2012,2030,74in,#623a2f,grn,087499704,,,true\n" +
+		"4,,,,59cm,,,1,,foo:bar pid:2,false\n"
+	if got := b.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteCSVEmptyValue(t *testing.T) {
+	b, err := ReadBatch(strings.NewReader("byr: byr:1990\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	if err := WriteCSV(&out, b.Records, DefaultSchema()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The empty byr was given first, so it has the column.
+	want := "1,,,,,,,,,byr:1990,false\n"
+	if got := strings.SplitAfter(out.String(), "\n")[1]; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestWriteCanonical(t *testing.T) {
+	var b strings.Builder
+	if err := WriteCanonical(&b, readExportBatch(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "" +
+		"byr:1980 ecl:grn eyr:2030 hcl:#623a2f hgt:74in iyr:2012 pid:087499704\n" +
+		"\n" +
+		"foo:bar hgt:59cm pid:1 pid:2\n"
+	if got := b.String(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// The canonical form is a batch too, and already canonical.
+	again, err := ReadBatch(strings.NewReader(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Reset()
+	if err := WriteCanonical(&b, again.Records); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != want {
+		t.Errorf("canonical form changed when written again:\n%s", b.String())
+	}
+}
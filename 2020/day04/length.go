@@ -0,0 +1,152 @@
+package day04
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A Length is a distance in micrometres, which every unit we know is a whole
+// number of. Millimetres are the canonical unit for writing lengths out.
+type Length int64
+
+// Lengths of the units.
+const (
+	Micrometre Length = 1
+	Millimetre        = 1000 * Micrometre
+	Centimetre        = 10 * Millimetre
+	Metre             = 1000 * Millimetre
+	Inch              = 25400 * Micrometre
+	Foot              = 12 * Inch
+)
+
+// FeetAndInches is the name of the notation for heights like 5'11", feet and
+// then inches. The inches can be left off, as in 6'.
+const FeetAndInches = `ft'in"`
+
+var lengthUnits = map[string]Length{
+	"mm": Millimetre,
+	"cm": Centimetre,
+	"m":  Metre,
+	"in": Inch,
+	"ft": Foot,
+}
+
+// RegisterUnit makes a unit known to ParseLength, written after the number
+// as name. It panics if the name is already taken, or isn't just letters.
+func RegisterUnit(name string, size Length) {
+	if _, dup := lengthUnits[name]; dup || name == FeetAndInches {
+		panic(fmt.Sprintf("day04: RegisterUnit called twice for %q", name))
+	}
+	if name == "" || strings.Trim(name, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		panic(fmt.Sprintf("day04: unit name %q isn't just letters", name))
+	}
+	lengthUnits[name] = size
+}
+
+// Units returns the names of every known unit and notation, sorted.
+func Units() []string {
+	names := []string{FeetAndInches}
+	for name := range lengthUnits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var (
+	reLength        = regexp.MustCompile(`^(\d+(?:\.\d+)?)([A-Za-z]+)$`)
+	reFeetAndInches = regexp.MustCompile(`^(\d+)'(?:(\d+(?:\.\d+)?)")?$`)
+)
+
+// ParseLength reads a number followed by a unit, like "183cm", "1.83m" or
+// "74in", or feet and inches, like 6'2". It also returns the unit, or
+// FeetAndInches.
+func ParseLength(s string) (Length, string, error) {
+	if m := reFeetAndInches.FindStringSubmatch(s); m != nil {
+		feet, err := scale(m[1], Foot)
+		if err != nil {
+			return 0, "", err
+		}
+		var inches Length
+		if m[2] != "" {
+			if inches, err = scale(m[2], Inch); err != nil {
+				return 0, "", err
+			}
+		}
+		return feet + inches, FeetAndInches, nil
+	}
+
+	m := reLength.FindStringSubmatch(s)
+	if m == nil {
+		return 0, "", fmt.Errorf("%q isn't a number followed by a unit", s)
+	}
+	size, ok := lengthUnits[m[2]]
+	if !ok {
+		return 0, "", fmt.Errorf("unit %q isn't one of %s", m[2], strings.Join(Units(), " "))
+	}
+	l, err := scale(m[1], size)
+	return l, m[2], err
+}
+
+// scale returns a decimal number of units as a Length.
+func scale(number string, unit Length) (Length, error) {
+	whole, frac, _ := strings.Cut(number, ".")
+	// No unit has more than a million micrometres, so a longer fraction
+	// can't come out whole, and would overflow div below.
+	if len(frac) > 6 {
+		return 0, fmt.Errorf("%s is finer than a micrometre", number)
+	}
+
+	n, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s is too big", number)
+	}
+	div := int64(1)
+	for range frac {
+		div *= 10
+	}
+
+	// n/div units, done so as not to lose anything to rounding.
+	if n > (1<<62)/int64(unit) {
+		return 0, fmt.Errorf("%s is too big", number)
+	}
+	l := n * int64(unit)
+	if l%div != 0 {
+		return 0, fmt.Errorf("%s is finer than a micrometre", number)
+	}
+	return Length(l / div), nil
+}
+
+// Millimetres returns a length in millimetres, for lengths given in JSON.
+func Millimetres(mm float64) Length {
+	if mm < 0 {
+		return Length(mm*1000 - 0.5)
+	}
+	return Length(mm*1000 + 0.5)
+}
+
+// String writes a length in millimetres, the canonical unit.
+func (l Length) String() string {
+	sign := ""
+	if l < 0 {
+		sign, l = "-", -l
+	}
+	s := fmt.Sprintf("%s%d", sign, l/Millimetre)
+	if frac := l % Millimetre; frac != 0 {
+		s += strings.TrimRight(fmt.Sprintf(".%03d", frac), "0")
+	}
+	return s + "mm"
+}
+
+// NormalizeLength rewrites a length in canonical units, so "6'" and "72in"
+// both come out as "1828.8mm".
+func NormalizeLength(s string) (string, error) {
+	l, _, err := ParseLength(s)
+	if err != nil {
+		return "", err
+	}
+	return l.String(), nil
+}
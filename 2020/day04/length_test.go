@@ -0,0 +1,113 @@
+package day04
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseLength(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Length
+		unit string
+		err  string
+	}{
+		{"1830mm", 1830 * Millimetre, "mm", ""},
+		{"183cm", 1830 * Millimetre, "cm", ""},
+		{"1.83m", 1830 * Millimetre, "m", ""},
+		{"72in", 1828800 * Micrometre, "in", ""},
+		{"6ft", 1828800 * Micrometre, "ft", ""},
+		{"6'", 1828800 * Micrometre, FeetAndInches, ""},
+		{`6'2"`, 1879600 * Micrometre, FeetAndInches, ""},
+		{`5'11.5"`, 1816100 * Micrometre, FeetAndInches, ""},
+		{"0.001mm", Micrometre, "mm", ""},
+
+		{"183", 0, "", "isn't a number followed by a unit"},
+		{"cm", 0, "", "isn't a number followed by a unit"},
+		{"-5cm", 0, "", "isn't a number followed by a unit"},
+		{"1.cm", 0, "", "isn't a number followed by a unit"},
+		{"6 ft", 0, "", "isn't a number followed by a unit"},
+		{`6"2'`, 0, "", "isn't a number followed by a unit"},
+		{"6yd", 0, "", `unit "yd" isn't one of`},
+		{"0.0001mm", 0, "", "finer than a micrometre"},
+		{"0." + strings.Repeat("0", 70) + "1cm", 0, "", "finer than a micrometre"},
+		{"99999999999999999999mm", 0, "", "too big"},
+		{"9999999999999m", 0, "", "too big"},
+	}
+
+	for _, tt := range tests {
+		got, unit, err := ParseLength(tt.in)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("ParseLength(%q) = %v, want an error containing %q", tt.in, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want || unit != tt.unit {
+			t.Errorf("ParseLength(%q) = %v, %q, %v, want %v, %q", tt.in, got, unit, err, tt.want, tt.unit)
+		}
+	}
+}
+
+func TestNormalizeLength(t *testing.T) {
+	tests := map[string]string{
+		"183cm":  "1830mm",
+		"6'":     "1828.8mm",
+		"72in":   "1828.8mm",
+		"59in":   "1498.6mm",
+		"1.5mm":  "1.5mm",
+		"0.25mm": "0.25mm",
+		"0m":     "0mm",
+	}
+	for in, want := range tests {
+		if got, err := NormalizeLength(in); err != nil || got != want {
+			t.Errorf("NormalizeLength(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+}
+
+// The default schema's one range, 1498.6-1930.4mm in whole numbers of either
+// unit, allows exactly the puzzle's 150-193cm and 59-76in.
+func TestPuzzleHeights(t *testing.T) {
+	rule := DefaultSchema().field("hgt")
+	for cm := 100; cm <= 250; cm++ {
+		value := strconv.Itoa(cm) + "cm"
+		want := cm >= 150 && cm <= 193
+		if got := rule.Check(value) == nil; got != want {
+			t.Errorf("%s valid = %v, want %v", value, got, want)
+		}
+	}
+	for in := 40; in <= 100; in++ {
+		value := strconv.Itoa(in) + "in"
+		want := in >= 59 && in <= 76
+		if got := rule.Check(value) == nil; got != want {
+			t.Errorf("%s valid = %v, want %v", value, got, want)
+		}
+	}
+	for _, value := range []string{"175.5cm", "60.5in"} {
+		if rule.Check(value) == nil {
+			t.Errorf("%s valid = true, want false", value)
+		}
+	}
+}
+
+func TestRegisterUnit(t *testing.T) {
+	RegisterUnit("hand", 4*Inch)
+	defer delete(lengthUnits, "hand")
+
+	if got, err := NormalizeLength("15hand"); err != nil || got != "1524mm" {
+		t.Errorf(`NormalizeLength("15hand") = %q, %v, want "1524mm"`, got, err)
+	}
+
+	for _, name := range []string{"cm", FeetAndInches, "", "m2"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterUnit(%q) didn't panic", name)
+				}
+			}()
+			RegisterUnit(name, Millimetre)
+		}()
+	}
+}
@@ -0,0 +1,182 @@
+package day04
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// A Result is a record validated by a Pipeline.
+type Result struct {
+	Index    int // the record's place in the batch, counting from 0
+	Record   *Record
+	Findings []Finding
+}
+
+// Valid reports whether the record passed, with nothing worse than warnings.
+func (r Result) Valid() bool {
+	return passes(r.Findings)
+}
+
+// PipelineStats says how much a Pipeline got through, and how fast.
+type PipelineStats struct {
+	Records int
+	Valid   int
+	Bytes   int64
+	Elapsed time.Duration
+	Workers int // how many validated records, after defaulting
+}
+
+// RecordsPerSecond returns the rate the records were validated at.
+func (s PipelineStats) RecordsPerSecond() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Records) / s.Elapsed.Seconds()
+}
+
+// BytesPerSecond returns the rate the batch was read at.
+func (s PipelineStats) BytesPerSecond() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Bytes) / s.Elapsed.Seconds()
+}
+
+// A Pipeline validates a batch too big to read in whole. One goroutine reads
+// records, Workers goroutines validate them against the Schema, and the
+// results are handed back in the order the records came in.
+//
+// At most Window records are held at once, read but not yet handed back, so
+// memory stays bounded however big the batch is. A slow record holds up the
+// ones after it once the window fills up.
+type Pipeline struct {
+	Schema  *Schema // compiled once, shared by all the workers
+	Workers int     // defaults to runtime.NumCPU()
+	Window  int     // defaults to 64 records per worker
+}
+
+type job struct {
+	index  int
+	record *Record
+}
+
+// errStopped stops the reader when the pipeline is shutting down early.
+var errStopped = errors.New("pipeline stopped")
+
+// Run validates every record read from r, calling found with each result in
+// order. It stops at the first error from reading the batch or from found.
+func (p *Pipeline) Run(r io.Reader, found func(Result) error) (PipelineStats, error) {
+	workers := p.Workers
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	window := p.Window
+	if window < 1 {
+		window = 64 * workers
+	}
+	schema := p.Schema
+	if schema == nil {
+		schema = DefaultSchema()
+	}
+
+	start := time.Now()
+	counter := &countingReader{r: r}
+
+	// Closing stop tells the reader and workers to give up.
+	stop := make(chan struct{})
+	// slots holds a token for every record in the window.
+	slots := make(chan struct{}, window)
+	jobs := make(chan job)
+	results := make(chan Result, window)
+	readErr := make(chan error, 1)
+
+	go func() {
+		defer close(jobs)
+		sc := NewScanner(counter)
+		for i := 0; sc.Scan(); i++ {
+			select {
+			case slots <- struct{}{}:
+			case <-stop:
+				readErr <- errStopped
+				return
+			}
+			select {
+			case jobs <- job{i, sc.Record()}:
+			case <-stop:
+				readErr <- errStopped
+				return
+			}
+		}
+		readErr <- sc.Err()
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				// results has room for the whole window, so this never
+				// blocks.
+				results <- Result{Index: j.index, Record: j.record, Findings: schema.Validate(j.record)}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	stats := PipelineStats{Workers: workers}
+	var err error
+	pending := map[int]Result{}
+	next := 0
+	for res := range results {
+		if err != nil {
+			// Drain what's left so the workers can finish.
+			continue
+		}
+
+		pending[res.Index] = res
+		for {
+			res, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			<-slots
+
+			stats.Records++
+			if res.Valid() {
+				stats.Valid++
+			}
+			if err = found(res); err != nil {
+				close(stop)
+				break
+			}
+		}
+	}
+
+	if rerr := <-readErr; err == nil && rerr != errStopped {
+		err = rerr
+	}
+	stats.Bytes = counter.n
+	stats.Elapsed = time.Since(start)
+	return stats, err
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
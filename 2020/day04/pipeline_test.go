@@ -0,0 +1,90 @@
+package day04
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestPipelineOrder(t *testing.T) {
+	batch := strings.Repeat(invalidPassports+"\n"+validPassports+"\n", 50)
+	want, err := ReadBatch(strings.NewReader(batch))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, workers := range []int{1, 3, 8} {
+		p := &Pipeline{Workers: workers, Window: 5}
+
+		var got []Result
+		stats, err := p.Run(strings.NewReader(batch), func(res Result) error {
+			got = append(got, res)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(got) != len(want.Records) {
+			t.Fatalf("%d workers: got %d results, want %d", workers, len(got), len(want.Records))
+		}
+		for i, res := range got {
+			if res.Index != i || res.Record.FirstLine != want.Records[i].FirstLine {
+				t.Fatalf("%d workers: result %d is record %d from line %d", workers, i, res.Index, res.Record.FirstLine)
+			}
+			if f := DefaultSchema().Validate(want.Records[i]); !reflect.DeepEqual(res.Findings, f) {
+				t.Fatalf("%d workers: record %d findings = %v, want %v", workers, i, res.Findings, f)
+			}
+		}
+
+		if stats.Records != 400 || stats.Valid != 200 || stats.Bytes != int64(len(batch)) || stats.Workers != workers {
+			t.Errorf("%d workers: stats = %+v, want 400 records, 200 valid, %d bytes", workers, stats, len(batch))
+		}
+	}
+}
+
+func TestPipelineStops(t *testing.T) {
+	batch := strings.Repeat(validPassports+"\n", 100)
+	stop := errors.New("stop")
+
+	p := &Pipeline{Workers: 4, Window: 2}
+	seen := 0
+	stats, err := p.Run(strings.NewReader(batch), func(Result) error {
+		seen++
+		if seen == 10 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Errorf("Run = %v, want the error from found", err)
+	}
+	if seen != 10 || stats.Records != 10 {
+		t.Errorf("found called %d times, %d records counted, want 10", seen, stats.Records)
+	}
+}
+
+func TestPipelineDefaultWorkers(t *testing.T) {
+	p := &Pipeline{}
+	stats, err := p.Run(strings.NewReader(validPassports), func(Result) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Workers != runtime.NumCPU() {
+		t.Errorf("stats.Workers = %d, want runtime.NumCPU() = %d", stats.Workers, runtime.NumCPU())
+	}
+}
+
+func TestPipelineReadError(t *testing.T) {
+	r := io.MultiReader(strings.NewReader(validPassports), iotest.ErrReader(errors.New("disk on fire")))
+
+	p := &Pipeline{Workers: 2}
+	_, err := p.Run(r, func(Result) error { return nil })
+	if err == nil || err.Error() != "disk on fire" {
+		t.Errorf("Run = %v, want the read error", err)
+	}
+}
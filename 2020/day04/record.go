@@ -0,0 +1,238 @@
+package day04
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// A Field is one key:value pair from a passport record.
+type Field struct {
+	Key   string
+	Value string
+
+	Line, Col int // where the pair starts in the batch, counting from 1
+
+	// Malformed fields had no ':' in them, or nothing before it. The whole
+	// token is in Key.
+	Malformed bool
+}
+
+func (f Field) String() string {
+	if f.Malformed {
+		return f.Key
+	}
+	return f.Key + ":" + f.Value
+}
+
+// A Record is one passport from a batch, with every field in the order it was
+// written, duplicates, unknown keys, mistakes and all.
+type Record struct {
+	Fields []Field
+
+	FirstLine, LastLine int // the lines the record is written on
+
+	raw string // exactly as written, line endings and all
+}
+
+// Get returns the value of the first field with the given key, or false if
+// the record hasn't got one.
+func (r *Record) Get(key string) (string, bool) {
+	for _, f := range r.Fields {
+		if f.Key == key && !f.Malformed {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// Duplicates returns the fields whose keys were already used earlier in the
+// record.
+func (r *Record) Duplicates() []Field {
+	var dups []Field
+	seen := map[string]bool{}
+	for _, f := range r.Fields {
+		if f.Malformed {
+			continue
+		}
+		if seen[f.Key] {
+			dups = append(dups, f)
+		}
+		seen[f.Key] = true
+	}
+	return dups
+}
+
+// String writes the record's fields on one line, in order.
+func (r *Record) String() string {
+	pairs := make([]string, len(r.Fields))
+	for i, f := range r.Fields {
+		pairs[i] = f.String()
+	}
+	return strings.Join(pairs, " ")
+}
+
+// A Batch is a whole batch file of passport records, kept so that it can be
+// written back out exactly as it was read.
+type Batch struct {
+	Records []*Record
+
+	// gaps[i] is the blank lines before Records[i], and the last one whatever
+	// comes after the last record.
+	gaps []string
+}
+
+// ReadBatch reads a whole batch with a Scanner, keeping everything between
+// the records so that it can be written back out.
+func ReadBatch(r io.Reader) (*Batch, error) {
+	b := &Batch{}
+	sc := NewScanner(r)
+	for sc.Scan() {
+		b.Records = append(b.Records, sc.Record())
+		b.gaps = append(b.gaps, sc.gap)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	b.gaps = append(b.gaps, sc.next.String())
+
+	return b, nil
+}
+
+// A Scanner reads the records of a batch one at a time, so a batch needn't
+// fit in memory. Records are separated by blank lines; lines of nothing but
+// spaces and tabs count as blank. Within a record, fields are separated by
+// spaces or tabs, and may be spread over several lines.
+//
+// A Scanner doesn't judge what it reads: a token without a ':' becomes a
+// Malformed field, and keys are kept whether they're known or not.
+type Scanner struct {
+	r       *bufio.Reader
+	lineNum int
+	record  *Record
+	err     error
+	done    bool
+
+	gap  string          // the blank lines before record
+	next strings.Builder // the blank lines read since
+}
+
+// NewScanner returns a Scanner reading from r.
+func NewScanner(r io.Reader) *Scanner {
+	return &Scanner{r: bufio.NewReader(r)}
+}
+
+// Scan reads the next record, which Record then returns. It returns false at
+// the end of the batch, or on an error, which Err then returns.
+func (s *Scanner) Scan() bool {
+	s.record = nil
+	if s.done {
+		return false
+	}
+
+	var record *Record
+	var raw strings.Builder
+	for {
+		line, err := s.r.ReadString('\n')
+		if line != "" {
+			s.lineNum++
+			text := strings.TrimRight(line, "\r\n")
+
+			if strings.Trim(text, " \t") == "" {
+				s.next.WriteString(line)
+				if record != nil {
+					// The blank line belongs before the next record.
+					record.raw = raw.String()
+					s.record = record
+					return true
+				}
+			} else {
+				if record == nil {
+					record = &Record{FirstLine: s.lineNum}
+					s.gap = s.next.String()
+					s.next.Reset()
+				}
+				record.LastLine = s.lineNum
+				record.Fields = append(record.Fields, splitFields(s.lineNum, text)...)
+				raw.WriteString(line)
+			}
+		}
+
+		if err != nil {
+			s.done = true
+			if err != io.EOF {
+				s.err = err
+				return false
+			}
+			if record == nil {
+				return false
+			}
+			record.raw = raw.String()
+			s.record = record
+			return true
+		}
+	}
+}
+
+// Record returns the record read by the last call to Scan.
+func (s *Scanner) Record() *Record {
+	return s.record
+}
+
+// Err returns the error that stopped Scan, if it wasn't the end of the batch.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// splitFields splits one line of a record into its fields.
+func splitFields(lineNum int, line string) []Field {
+	var fields []Field
+	for col := 0; col < len(line); {
+		if line[col] == ' ' || line[col] == '\t' {
+			col++
+			continue
+		}
+		end := strings.IndexAny(line[col:], " \t")
+		if end < 0 {
+			end = len(line)
+		} else {
+			end += col
+		}
+
+		token := line[col:end]
+		f := Field{Line: lineNum, Col: col + 1}
+		if key, value, ok := strings.Cut(token, ":"); ok && key != "" {
+			f.Key, f.Value = key, value
+		} else {
+			f.Key, f.Malformed = token, true
+		}
+		fields = append(fields, f)
+
+		col = end
+	}
+	return fields
+}
+
+// Line returns the text of one of the record's lines, without its line
+// ending.
+func (r *Record) Line(lineNum int) string {
+	lines := strings.Split(r.raw, "\n")
+	i := lineNum - r.FirstLine
+	if i < 0 || i >= len(lines) {
+		return ""
+	}
+	return strings.TrimSuffix(lines[i], "\r")
+}
+
+// WriteTo writes the batch out exactly as it was read.
+func (b *Batch) WriteTo(w io.Writer) (int64, error) {
+	var sb strings.Builder
+	for i, r := range b.Records {
+		sb.WriteString(b.gaps[i])
+		sb.WriteString(r.raw)
+	}
+	sb.WriteString(b.gaps[len(b.gaps)-1])
+
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
@@ -0,0 +1,107 @@
+package day04
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+)
+
+func TestBatchRoundTrip(t *testing.T) {
+	tests := []string{
+		"",
+		"\n\n",
+		example,
+		strings.TrimSuffix(example, "\n"),
+		strings.ReplaceAll(example, "\n", "\r\n"),
+		"\n\nbyr:1980  iyr:2012\t\teyr:2030 \n \t\nhgt:74in\n\n\n\npid:1 pid:2 junk\n\n",
+		"a:1\r\nb:2\n\r\nc:3",
+	}
+
+	for _, text := range tests {
+		b, err := ReadBatch(strings.NewReader(text))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out strings.Builder
+		if _, err := b.WriteTo(&out); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != text {
+			t.Errorf("round trip of %q gave %q", text, out.String())
+		}
+	}
+}
+
+func TestReadBatch(t *testing.T) {
+	text := "\nbyr:1980  iyr:2012\r\n\teyr:20:30\n \nhgt:74in byr:1 junk :x\n"
+	b, err := ReadBatch(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Records) != 2 {
+		t.Fatalf("read %d records, want 2", len(b.Records))
+	}
+
+	first := b.Records[0]
+	wantFields := []Field{
+		{Key: "byr", Value: "1980", Line: 2, Col: 1},
+		{Key: "iyr", Value: "2012", Line: 2, Col: 11},
+		{Key: "eyr", Value: "20:30", Line: 3, Col: 2},
+	}
+	if !reflect.DeepEqual(first.Fields, wantFields) {
+		t.Errorf("first record fields = %+v, want %+v", first.Fields, wantFields)
+	}
+	if first.FirstLine != 2 || first.LastLine != 3 {
+		t.Errorf("first record spans lines %d-%d, want 2-3", first.FirstLine, first.LastLine)
+	}
+	if got := first.Line(3); got != "\teyr:20:30" {
+		t.Errorf("Line(3) = %q", got)
+	}
+
+	second := b.Records[1]
+	if second.FirstLine != 5 || second.LastLine != 5 {
+		t.Errorf("second record spans lines %d-%d, want 5-5", second.FirstLine, second.LastLine)
+	}
+	if got := second.String(); got != "hgt:74in byr:1 junk :x" {
+		t.Errorf("second record = %q", got)
+	}
+	if !second.Fields[2].Malformed || !second.Fields[3].Malformed {
+		t.Errorf("junk and :x should be malformed: %+v", second.Fields)
+	}
+	if v, ok := second.Get("byr"); !ok || v != "1" {
+		t.Errorf("Get(byr) = %q, %v", v, ok)
+	}
+	if _, ok := second.Get("junk"); ok {
+		t.Error("Get found a malformed field")
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	b, err := ReadBatch(strings.NewReader("byr:1 iyr:2\nbyr:3 byr:4\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := b.Records[0]
+
+	if v, _ := r.Get("byr"); v != "1" {
+		t.Errorf("Get(byr) = %q, want the first one", v)
+	}
+	var values []string
+	for _, f := range r.Duplicates() {
+		values = append(values, f.Value)
+	}
+	if want := []string{"3", "4"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("Duplicates = %v, want %v", values, want)
+	}
+}
+
+func TestReadPassportsMalformed(t *testing.T) {
+	_, err := readPassports(strings.NewReader("byr:1980\n\niyr:2012 oops\n"))
+	var pe *aoc.ParseError
+	if !errors.As(err, &pe) || pe.Line != 3 || pe.Col != 10 || pe.Text != "iyr:2012 oops" {
+		t.Errorf("readPassports = %v, want an error at 3:10", err)
+	}
+}
@@ -0,0 +1,398 @@
+package day04
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A Schema says which fields a passport must or may have, and what makes
+// their values valid. It need only list the fields it checks: fields it
+// doesn't list are unknown, which is worth a warning but, unless the schema is
+// Strict, leaves a passport valid. Schemas are written in JSON, like
+// schema2020.json, which holds the rules from the puzzle:
+//
+//	{"fields": [
+//		{"name": "byr", "pattern": "\\d{4}", "range": {"min": 1920, "max": 2002}},
+//		{"name": "hgt", "length": {"units": ["cm", "in"], "min": 1498.6, "max": 1930.4, "whole": true}},
+//		{"name": "ecl", "enum": ["amb", "blu", "brn", "gry", "grn", "hzl", "oth"]},
+//		{"name": "cid", "optional": true},
+//		...
+//	]}
+type Schema struct {
+	Fields []*FieldRule `json:"fields"`
+
+	// Strict schemas also count malformed, unknown and repeated fields
+	// against a passport, rather than just warning about them.
+	Strict bool `json:"strict,omitempty"`
+}
+
+// A FieldRule is what a Schema asks of one field. A value must pass every
+// check the rule has; a rule with none accepts any value.
+type FieldRule struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// Optional fields may be left out of a passport, but are still checked
+	// when they're there.
+	Optional bool `json:"optional,omitempty"`
+
+	// Pattern is a regular expression the whole value must match.
+	Pattern string `json:"pattern,omitempty"`
+
+	// Range is the lowest and highest the value may be, as a whole number.
+	Range *Range `json:"range,omitempty"`
+
+	// Enum lists the only values allowed.
+	Enum []string `json:"enum,omitempty"`
+
+	// Length makes the value a length, like "183cm", in a range allowed
+	// however it's written.
+	Length *LengthRule `json:"length,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// A LengthRule allows one inclusive range of lengths, given in millimetres,
+// written in any of its units. A length is checked as a length, not as a
+// number of the unit it's written in: 1498.6-1930.4mm with whole numbers is
+// the puzzle's 150-193cm and 59-76in, since 59in is 1498.6mm and 76in is
+// 1930.4mm.
+type LengthRule struct {
+	Units []string `json:"units"` // units and notations allowed, from Units()
+	Min   float64  `json:"min"`   // in millimetres
+	Max   float64  `json:"max"`   // in millimetres
+
+	// Whole lengths must be a whole number of the unit they're written in,
+	// like 183cm and not 183.5cm.
+	Whole bool `json:"whole,omitempty"`
+
+	min, max Length
+}
+
+// A Range is an inclusive range of whole numbers.
+type Range struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+func (r Range) String() string {
+	return fmt.Sprintf("%d-%d", r.Min, r.Max)
+}
+
+//go:embed schema2020.json
+var schema2020 string
+
+// defaultSchema is the puzzle's own schema.
+var defaultSchema = mustLoadSchema(schema2020)
+
+func mustLoadSchema(text string) *Schema {
+	s, err := LoadSchema(strings.NewReader(text))
+	if err != nil {
+		panic(fmt.Sprintf("day04: bad built in schema: %v", err))
+	}
+	return s
+}
+
+// DefaultSchema returns the rules from the 2020 puzzle.
+func DefaultSchema() *Schema {
+	return defaultSchema
+}
+
+// LoadSchema reads a schema written in JSON, and checks that it makes sense.
+func LoadSchema(r io.Reader) (*Schema, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var s Schema
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+	if err := s.compile(); err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+	return &s, nil
+}
+
+// ReadSchemaFile loads the schema in the named file.
+func ReadSchemaFile(name string) (*Schema, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s, err := LoadSchema(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return s, nil
+}
+
+// compile checks the rules and compiles their patterns.
+func (s *Schema) compile() error {
+	if len(s.Fields) == 0 {
+		return errors.New("no fields")
+	}
+
+	seen := map[string]bool{}
+	for i, f := range s.Fields {
+		switch {
+		case f == nil || f.Name == "":
+			return fmt.Errorf("field %d has no name", i+1)
+		case seen[f.Name]:
+			return fmt.Errorf("field %q is there twice", f.Name)
+		case f.Range != nil && f.Range.Min > f.Range.Max:
+			return fmt.Errorf("field %q: range %v is backwards", f.Name, *f.Range)
+		}
+		seen[f.Name] = true
+
+		if l := f.Length; l != nil {
+			if len(l.Units) == 0 {
+				return fmt.Errorf("field %q: no units for the length", f.Name)
+			}
+			for _, unit := range l.Units {
+				if !contains(Units(), unit) {
+					return fmt.Errorf("field %q: unit %q isn't one of %s", f.Name, unit, strings.Join(Units(), " "))
+				}
+			}
+			l.min, l.max = Millimetres(l.Min), Millimetres(l.Max)
+			if l.min > l.max {
+				return fmt.Errorf("field %q: length range %v-%v is backwards", f.Name, l.min, l.max)
+			}
+		}
+
+		if f.Pattern != "" {
+			// Match the whole value, not just part of it.
+			re, err := regexp.Compile(`^(?:` + f.Pattern + `)$`)
+			if err != nil {
+				return fmt.Errorf("field %q: %w", f.Name, err)
+			}
+			f.pattern = re
+		}
+	}
+
+	return nil
+}
+
+// given returns the value of a passport's field, or false if it's missing.
+// A field with nothing after the ':' is as good as missing.
+func given(p *Record, name string) (string, bool) {
+	value, ok := p.Get(name)
+	return value, ok && value != ""
+}
+
+// Complete reports whether a passport has every field that isn't optional.
+// It doesn't look at what's in them, beyond their not being empty.
+func (s *Schema) Complete(p *Record) bool {
+	for _, f := range s.Fields {
+		if _, ok := given(p, f.Name); !ok && !f.Optional {
+			return false
+		}
+	}
+	return true
+}
+
+// Valid reports whether a passport is complete and every field the schema
+// knows about has a valid value.
+func (s *Schema) Valid(p *Record) bool {
+	return passes(s.Validate(p))
+}
+
+// passes reports whether a passport with these findings is valid, which it
+// is if they're all warnings.
+func passes(findings []Finding) bool {
+	for _, f := range findings {
+		if !f.Warning {
+			return false
+		}
+	}
+	return true
+}
+
+// The rules a Finding can be about: a field that must be there, one of the
+// checks in its FieldRule, or the fields of a record being well formed,
+// known to the schema and given only once.
+const (
+	RuleRequired  = "required"
+	RulePattern   = "pattern"
+	RuleRange     = "range"
+	RuleEnum      = "enum"
+	RuleLength    = "length"
+	RuleSyntax    = "syntax"
+	RuleUnknown   = "unknown"
+	RuleDuplicate = "duplicate"
+)
+
+// A Finding is one thing wrong with a passport.
+type Finding struct {
+	Field   string `json:"field"`
+	Value   string `json:"value"`   // "" for a missing field
+	Rule    string `json:"rule"`    // which rule it broke, like RuleRange
+	Message string `json:"message"` // what's wrong, like "2031 is outside 2020-2030"
+
+	// Warnings are worth knowing about, but leave the passport valid.
+	Warning bool `json:"warning,omitempty"`
+}
+
+func (f Finding) String() string {
+	if f.Rule == RuleRequired || f.Rule == RuleSyntax {
+		return fmt.Sprintf("%s: %s", f.Field, f.Message)
+	}
+	return fmt.Sprintf("%s:%s: %s", f.Field, f.Value, f.Message)
+}
+
+// Validate returns everything wrong with a passport: first each missing
+// field and the first rule each field's value breaks, in the schema's field
+// order, and then any malformed, unknown or repeated fields in the record,
+// which are only warnings unless the schema is Strict. Only the first of a
+// repeated field is checked against its rule.
+func (s *Schema) Validate(p *Record) []Finding {
+	var findings []Finding
+	for _, f := range s.Fields {
+		value, ok := given(p, f.Name)
+		if !ok {
+			if !f.Optional {
+				findings = append(findings, Finding{Field: f.Name, Rule: RuleRequired, Message: "missing"})
+			}
+			continue
+		}
+		if rule, message := f.check(value); rule != "" {
+			findings = append(findings, Finding{Field: f.Name, Value: value, Rule: rule, Message: message})
+		}
+	}
+
+	warning := !s.Strict
+	for _, f := range p.Fields {
+		switch {
+		case f.Malformed:
+			findings = append(findings, Finding{Field: f.Key, Rule: RuleSyntax,
+				Message: fmt.Sprintf("not key:value, on line %d", f.Line), Warning: warning})
+		case s.field(f.Key) == nil:
+			findings = append(findings, Finding{Field: f.Key, Value: f.Value, Rule: RuleUnknown,
+				Message: fmt.Sprintf("not a passport field, on line %d", f.Line), Warning: warning})
+		}
+	}
+	for _, f := range p.Duplicates() {
+		findings = append(findings, Finding{Field: f.Key, Value: f.Value, Rule: RuleDuplicate,
+			Message: fmt.Sprintf("given again on line %d", f.Line), Warning: warning})
+	}
+
+	return findings
+}
+
+// field returns the rule for the named field, or nil if there isn't one.
+func (s *Schema) field(name string) *FieldRule {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// Check returns what's wrong with a field's value, or nil if nothing is.
+func (f *FieldRule) Check(value string) error {
+	if rule, message := f.check(value); rule != "" {
+		return errors.New(message)
+	}
+	return nil
+}
+
+// check returns the first rule a field's value breaks and what's wrong with
+// it, or "" if it breaks none.
+func (f *FieldRule) check(value string) (rule, message string) {
+	if f.pattern != nil && !f.pattern.MatchString(value) {
+		return RulePattern, fmt.Sprintf("doesn't match %s", f.Pattern)
+	}
+
+	if f.Range != nil {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return RuleRange, "not a whole number"
+		}
+		if n < f.Range.Min || n > f.Range.Max {
+			return RuleRange, fmt.Sprintf("%d is outside %v", n, *f.Range)
+		}
+	}
+
+	if len(f.Enum) > 0 && !contains(f.Enum, value) {
+		return RuleEnum, fmt.Sprintf("not one of %s", strings.Join(f.Enum, " "))
+	}
+
+	if l := f.Length; l != nil {
+		length, unit, err := ParseLength(value)
+		if err != nil {
+			return RuleLength, err.Error()
+		}
+		if !contains(l.Units, unit) {
+			return RuleLength, fmt.Sprintf("unit %q isn't one of %s", unit, strings.Join(l.Units, " "))
+		}
+		// The only way to write part of a unit is with a decimal point.
+		if l.Whole && strings.Contains(value, ".") {
+			return RuleLength, fmt.Sprintf("%s isn't a whole number of %s", value, unit)
+		}
+		if length < l.min || length > l.max {
+			return RuleLength, fmt.Sprintf("%s is %v, outside %v-%v", value, length, l.min, l.max)
+		}
+	}
+
+	return "", ""
+}
+
+// A Reason is one kind of failure, a field and the rule it broke, and how
+// many passports it happened in.
+type Reason struct {
+	Field string
+	Rule  string
+	Count int
+}
+
+// Summarize counts the passports in a batch failing each way, by field and
+// rule, most common first. A passport breaking the same rule for the same
+// field twice, like a pid given three times, counts once.
+func Summarize(findings [][]Finding) []Reason {
+	counts := map[[2]string]int{}
+	for _, list := range findings {
+		seen := map[[2]string]bool{}
+		for _, f := range list {
+			k := [2]string{f.Field, f.Rule}
+			if !seen[k] {
+				seen[k] = true
+				counts[k]++
+			}
+		}
+	}
+
+	reasons := make([]Reason, 0, len(counts))
+	for k, n := range counts {
+		reasons = append(reasons, Reason{Field: k[0], Rule: k[1], Count: n})
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		a, b := reasons[i], reasons[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		return a.Rule < b.Rule
+	})
+	return reasons
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
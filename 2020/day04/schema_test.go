@@ -0,0 +1,263 @@
+package day04
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{"not json", `fields:`, "invalid character"},
+		{"unknown key", `{"fields": [{"name": "byr", "regex": "x"}]}`, "unknown field"},
+		{"no fields", `{"fields": []}`, "no fields"},
+		{"no name", `{"fields": [{"pattern": "x"}]}`, "no name"},
+		{"twice", `{"fields": [{"name": "byr"}, {"name": "byr"}]}`, "twice"},
+		{"bad pattern", `{"fields": [{"name": "byr", "pattern": "("}]}`, "missing closing )"},
+		{"backwards range", `{"fields": [{"name": "byr", "range": {"min": 2, "max": 1}}]}`, "backwards"},
+		{"old units", `{"fields": [{"name": "hgt", "units": {"cm": {"min": 1, "max": 2}}}]}`, "unknown field"},
+		{"no length units", `{"fields": [{"name": "hgt", "length": {"min": 1, "max": 2}}]}`, "no units"},
+		{"unknown length unit", `{"fields": [{"name": "hgt", "length": {"units": ["yd"], "min": 1, "max": 2}}]}`, `unit "yd" isn't one of`},
+		{"backwards length", `{"fields": [{"name": "hgt", "length": {"units": ["cm"], "min": 2, "max": 1}}]}`, "length range 2mm-1mm is backwards"},
+	}
+
+	for _, tt := range tests {
+		_, err := LoadSchema(strings.NewReader(tt.json))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: LoadSchema = %v, want an error containing %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestFieldRuleCheck(t *testing.T) {
+	rules := map[string]*FieldRule{}
+	for _, f := range DefaultSchema().Fields {
+		rules[f.Name] = f
+	}
+
+	tests := []struct {
+		field, value string
+		want         string
+	}{
+		{"byr", "1980", ""},
+		{"byr", "198", `doesn't match \d{4}`},
+		{"eyr", "2031", "2031 is outside 2020-2030"},
+		{"hgt", "183cm", ""},
+		{"hgt", "76in", ""},
+		{"hgt", "183", `"183" isn't a number followed by a unit`},
+		{"hgt", "cm", `"cm" isn't a number followed by a unit`},
+		{"hgt", "6ft", `unit "ft" isn't one of cm in`},
+		{"hgt", "1.83m", `unit "m" isn't one of cm in`},
+		{"hgt", "6yd", `unit "yd" isn't one of cm ft ft'in" in m mm`},
+		{"hgt", "77in", "77in is 1955.8mm, outside 1498.6mm-1930.4mm"},
+		{"hgt", "58in", "58in is 1473.2mm, outside 1498.6mm-1930.4mm"},
+		{"hgt", "149cm", "149cm is 1490mm, outside 1498.6mm-1930.4mm"},
+		{"hgt", "194cm", "194cm is 1940mm, outside 1498.6mm-1930.4mm"},
+		{"hgt", "149.9cm", "149.9cm isn't a whole number of cm"},
+		{"hgt", "193.04cm", "193.04cm isn't a whole number of cm"},
+		{"hgt", "150.0cm", "150.0cm isn't a whole number of cm"},
+		{"hgt", "59.5in", "59.5in isn't a whole number of in"},
+		{"ecl", "wat", "not one of amb blu brn gry grn hzl oth"},
+		{"cid", "anything", ""},
+	}
+
+	for _, tt := range tests {
+		err := rules[tt.field].Check(tt.value)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("%s:%s = %q, want %q", tt.field, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestLengthRule(t *testing.T) {
+	// Any height from 1.5m to 2m, in metric or feet and inches, to the
+	// nearest half inch or better.
+	s, err := LoadSchema(strings.NewReader(`{"fields": [{"name": "hgt", "length": {
+		"units": ["mm", "cm", "m", "ft'in\""], "min": 1500, "max": 2000
+	}}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule := s.Fields[0]
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"1.5m", ""},
+		{"183.5cm", ""},
+		{"2000mm", ""},
+		{`5'11.5"`, ""},
+		{`6'`, ""},
+		{`6'7"`, `6'7" is 2006.6mm, outside 1500mm-2000mm`},
+		{"72in", `unit "in" isn't one of mm cm m ft'in"`},
+	}
+	for _, tt := range tests {
+		err := rule.Check(tt.value)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSchemaFlag(t *testing.T) {
+	// Country IDs become compulsory, and eye colours more limited.
+	schema := `{"fields": [
+		{"name": "cid"},
+		{"name": "ecl", "enum": ["blu", "brn"]}
+	]}`
+	file := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(file, []byte(schema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newSolver()
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	s.Flags(fs)
+	if err := fs.Parse([]string{"-schema", file}); err != nil {
+		t.Fatal(err)
+	}
+
+	part1, err := s.Part1(strings.NewReader(validPassports))
+	if err != nil {
+		t.Fatal(err)
+	}
+	part2, err := s.Part2(strings.NewReader(validPassports))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if part1 != "2" || part2 != "1" {
+		t.Errorf("with -schema, parts = %s, %s, want 2, 1", part1, part2)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	records, err := readPassports(strings.NewReader(invalidPassports))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := DefaultSchema().Validate(records[0])
+	want := []Finding{
+		{Field: "eyr", Value: "1972", Rule: RuleRange, Message: "1972 is outside 2020-2030"},
+		{Field: "hgt", Value: "170", Rule: RuleLength, Message: `"170" isn't a number followed by a unit`},
+		{Field: "pid", Value: "186cm", Rule: RulePattern, Message: `doesn't match \d{9}`},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate = %v, want %v", got, want)
+	}
+
+	missing := DefaultSchema().Validate(&Record{Fields: []Field{{Key: "byr", Value: "1980"}}})
+	if len(missing) != 6 || missing[0] != (Finding{Field: "iyr", Rule: RuleRequired, Message: "missing"}) {
+		t.Errorf("Validate of a nearly empty passport = %v, want 6 missing fields starting with iyr", missing)
+	}
+}
+
+func TestEmptyValues(t *testing.T) {
+	// The first passport from validPassports, with nothing after byr: and
+	// cid:. An empty field is as good as missing.
+	const text = "pid:087499704 hgt:74in ecl:grn iyr:2012 eyr:2030 byr: hcl:#623a2f cid:\n"
+	records, err := readPassports(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := DefaultSchema()
+
+	if s.Complete(records[0]) {
+		t.Error("Complete with an empty byr = true, want false")
+	}
+	want := []Finding{{Field: "byr", Rule: RuleRequired, Message: "missing"}}
+	if got := s.Validate(records[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate with an empty byr = %v, want %v", got, want)
+	}
+
+	part1, err := newSolver().Part1(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if part1 != "0" {
+		t.Errorf("Part1 with an empty byr = %s, want 0", part1)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	records, err := readPassports(strings.NewReader(invalidPassports + "\n" + validPassports))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var findings [][]Finding
+	for _, p := range records {
+		findings = append(findings, DefaultSchema().Validate(p))
+	}
+
+	got := Summarize(findings)
+	if len(got) == 0 || got[0] != (Reason{Field: "eyr", Rule: RuleRange, Count: 3}) {
+		t.Fatalf("Summarize = %v, want eyr range failures on top", got)
+	}
+	total := 0
+	for _, r := range got {
+		total += r.Count
+	}
+	want := 0
+	for _, list := range findings {
+		want += len(list)
+	}
+	if total != want {
+		t.Errorf("Summarize counted %d failures, want %d", total, want)
+	}
+
+	// The same failure twice in one passport counts once.
+	twice := [][]Finding{
+		{{Field: "pid", Rule: RuleDuplicate}, {Field: "pid", Rule: RuleDuplicate}, {Field: "byr", Rule: RuleRequired}},
+		{{Field: "pid", Rule: RuleDuplicate}},
+	}
+	want2 := []Reason{{Field: "pid", Rule: RuleDuplicate, Count: 2}, {Field: "byr", Rule: RuleRequired, Count: 1}}
+	if got := Summarize(twice); !reflect.DeepEqual(got, want2) {
+		t.Errorf("Summarize with a repeated failure = %v, want %v", got, want2)
+	}
+}
+
+func TestValidateRecordProblems(t *testing.T) {
+	b, err := ReadBatch(strings.NewReader("pid:087499704 hgt:74in ecl:grn iyr:2012 eyr:2030 byr:1980\nhcl:#623a2f pid:1 foo:bar junk\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := DefaultSchema().Validate(b.Records[0])
+	want := []Finding{
+		{Field: "foo", Value: "bar", Rule: RuleUnknown, Message: "not a passport field, on line 2", Warning: true},
+		{Field: "junk", Rule: RuleSyntax, Message: "not key:value, on line 2", Warning: true},
+		{Field: "pid", Value: "1", Rule: RuleDuplicate, Message: "given again on line 2", Warning: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate = %v, want %v", got, want)
+	}
+	if !DefaultSchema().Valid(b.Records[0]) {
+		t.Error("Valid = false, want only warnings")
+	}
+
+	strict, err := LoadSchema(strings.NewReader(`{"strict": true, "fields": [{"name": "pid"}, {"name": "hcl"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = strict.Validate(b.Records[0])
+	if len(got) != 8 || got[0].Warning || strict.Valid(b.Records[0]) {
+		t.Errorf("strict Validate = %v, want 8 findings that aren't warnings", got)
+	}
+}
@@ -70,21 +70,19 @@
  * What is the ID of your seat?
  */
 
-package main
+package day05
 
 import (
 	"fmt"
 	"io"
-	"os"
 	"strings"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+	"github.com/tangledhelix/adventofcode2020/input"
 )
 
-func check(e error) {
-	if e != nil {
-		if e != io.EOF {
-			panic(e)
-		}
-	}
+func init() {
+	aoc.Register(2020, 5, solver{})
 }
 
 // A type defining a seat's metadata
@@ -97,8 +95,29 @@ type seat struct {
 
 // A type defining a grid size
 type gridSpec struct {
-	rows int /* total rows in the grid */
-	cols int /* total cols in the grid */
+	rows       int /* total rows in the grid */
+	cols       int /* total cols in the grid */
+	rowLetters int /* number of [FB] letters in a seat locator */
+	colLetters int /* number of [LR] letters in a seat locator */
+}
+
+// The most letters a seat locator may have. The seat map has 2^n seats for
+// n letters, so this keeps it to a million or so; the puzzle's plane has 10.
+const maxSeatLetters = 20
+
+// Work out the size of the plane from a seat locator. Each letter halves the
+// rows or columns left, so n row letters means 2^n rows. The puzzle's plane
+// has 7 row letters and 3 column letters, for 128 rows of 8 seats.
+func newGridSpec(seatLocator string) gridSpec {
+	rowLetters := len(seatLocator) - len(strings.TrimLeft(seatLocator, "FB"))
+	colLetters := len(seatLocator) - rowLetters
+
+	return gridSpec{
+		rows:       1 << rowLetters,
+		cols:       1 << colLetters,
+		rowLetters: rowLetters,
+		colLetters: colLetters,
+	}
 }
 
 func findRowOrCol(seatLocator string, highBound int, lowLetter string, highLetter string) int {
@@ -144,22 +163,46 @@ func findSeatLocation(seatLocator string, grid gridSpec) (int, int) {
 	return row, col
 }
 
-func calculateSeatId(row, col int) int {
-	return (row * 8) + col
+func calculateSeatId(row, col int, grid gridSpec) int {
+	return (row * grid.cols) + col
 }
 
-// Process a single seat, populating its metadata in seatList
-func processSeat(entryNumber int, seatLocator string, s *seat, grid gridSpec, seatMap *[128][8]bool) {
-	if seatLocator != "" {
-		s.locationCode = seatLocator
-		s.row, s.col = findSeatLocation(seatLocator, grid)
-		seatMap[s.row][s.col] = true
-		s.id = calculateSeatId(s.row, s.col)
+// Make sure a seat locator has the plane's number of row letters (F or B)
+// followed by its number of column letters (L or R), pointing at the first
+// letter that isn't.
+func checkSeatLocator(entryNumber int, seatLocator string, grid gridSpec) error {
+	for i, c := range seatLocator {
+		if i < grid.rowLetters && c != 'F' && c != 'B' {
+			return aoc.Errorf(entryNumber+1, i+1, seatLocator, "unknown row letter %q, want F or B", c)
+		}
+		if i >= grid.rowLetters && c != 'L' && c != 'R' {
+			return aoc.Errorf(entryNumber+1, i+1, seatLocator, "unknown column letter %q, want L or R", c)
+		}
 	}
+
+	if want := grid.rowLetters + grid.colLetters; len(seatLocator) != want {
+		return aoc.Errorf(entryNumber+1, len(seatLocator)+1, seatLocator, "seat locator has %d letters, want %d", len(seatLocator), want)
+	}
+
+	return nil
+}
+
+// Process a single seat, populating its metadata in s
+func processSeat(entryNumber int, seatLocator string, s *seat, grid gridSpec, seatMap [][]bool) error {
+	if err := checkSeatLocator(entryNumber, seatLocator, grid); err != nil {
+		return err
+	}
+
+	s.locationCode = seatLocator
+	s.row, s.col = findSeatLocation(seatLocator, grid)
+	seatMap[s.row][s.col] = true
+	s.id = calculateSeatId(s.row, s.col, grid)
+
+	return nil
 }
 
 // Locate the highest seat ID on our list
-func findHighestSeatId(seatList *[1000]seat) int {
+func findHighestSeatId(seatList []seat) int {
 	var highest int
 
 	for i := 0; i < len(seatList); i++ {
@@ -171,7 +214,7 @@ func findHighestSeatId(seatList *[1000]seat) int {
 	return highest
 }
 
-func printSeatMap(seatMap *[128][8]bool) {
+func printSeatMap(seatMap [][]bool) {
 	for row := 0; row < len(seatMap); row++ {
 		for col := 0; col < len(seatMap[row]); col++ {
 			if seatMap[row][col] {
@@ -184,43 +227,90 @@ func printSeatMap(seatMap *[128][8]bool) {
 	}
 }
 
-func findMySeatId(seatMap *[128][8]bool) int {
-	for row := 0; row < len(seatMap); row++ {
-		for col := 0; col < len(seatMap[row]); col++ {
-			if col != len(seatMap[row])-1 {
-				if !seatMap[row][col] && seatMap[row][col+1] && seatMap[row][col-1] {
-					return calculateSeatId(row, col)
-				}
-			}
+// Our seat is the empty one whose neighbours (by seat ID, so they may be on
+// the row before or after) are both taken.
+func findMySeatId(seatMap [][]bool, grid gridSpec) int {
+	taken := func(id int) bool {
+		return seatMap[id/grid.cols][id%grid.cols]
+	}
+
+	for id := 1; id < grid.rows*grid.cols-1; id++ {
+		if !taken(id) && taken(id-1) && taken(id+1) {
+			return id
 		}
 	}
 	return -1
 }
 
-func main() {
-	var seatList [1000]seat
-	grid := gridSpec{rows: 128, cols: 8}
-	var seatMap [128][8]bool
+type solver struct{}
+
+func (solver) Parse(input io.Reader) error {
+	_, _, _, err := readSeats(input)
+	return err
+}
 
+func readSeats(r io.Reader) ([]seat, [][]bool, gridSpec, error) {
 	// Read input file and break into lines
-	dat, err := os.ReadFile("input.txt")
-	check(err)
-	rawData := strings.Split(string(dat), "\n")
+	rawData, err := input.Lines(r)
+	if err != nil {
+		return nil, nil, gridSpec{}, err
+	}
+	if len(rawData) == 0 {
+		return nil, nil, gridSpec{}, aoc.Errorf(0, 0, "", "no boarding passes in input")
+	}
+
+	// The plane is sized by the first boarding pass; the rest must match it.
+	if len(rawData[0]) > maxSeatLetters {
+		return nil, nil, gridSpec{}, aoc.Errorf(1, maxSeatLetters+1, rawData[0], "seat locator has %d letters, want at most %d", len(rawData[0]), maxSeatLetters)
+	}
+	grid := newGridSpec(rawData[0])
+	if grid.rowLetters == 0 || grid.colLetters == 0 {
+		return nil, nil, gridSpec{}, aoc.Errorf(1, 1, rawData[0], "seat locator needs both row and column letters")
+	}
+
+	seatList := make([]seat, 0, len(rawData))
+	seatMap := make([][]bool, grid.rows)
+	for row := range seatMap {
+		seatMap[row] = make([]bool, grid.cols)
+	}
 
 	for i := 0; i < len(rawData); i++ {
-		processSeat(i, string(rawData[i]), &seatList[i], grid, &seatMap)
+		var s seat
+		if err := processSeat(i, string(rawData[i]), &s, grid, seatMap); err != nil {
+			return nil, nil, gridSpec{}, err
+		}
+		seatList = append(seatList, s)
 	}
 
 	// Show a literal map of the plane so we can spot our seat.
-	// printSeatMap(&seatMap)
+	// printSeatMap(seatMap)
+
+	return seatList, seatMap, grid, nil
+}
+
+func (solver) Part1(input io.Reader) (aoc.Answer, error) {
+	seatList, _, _, err := readSeats(input)
+	if err != nil {
+		return "", err
+	}
 
 	// Once seatList has been filled with the appropriate
 	// data, iterate over every boarding pass to find the highest
 	// seat ID in the list.
-	highestSeatId := findHighestSeatId(&seatList)
-	fmt.Println("Highest seat ID:", highestSeatId)
+	return aoc.Int(findHighestSeatId(seatList)), nil
+}
+
+func (solver) Part2(input io.Reader) (aoc.Answer, error) {
+	_, seatMap, grid, err := readSeats(input)
+	if err != nil {
+		return "", err
+	}
+
+	// Find our own seat
+	mySeatId := findMySeatId(seatMap, grid)
+	if mySeatId < 0 {
+		return "", fmt.Errorf("no empty seat between two occupied ones")
+	}
 
-	// Show us our own seat
-	mySeatId := findMySeatId(&seatMap)
-	fmt.Println("My seat ID:", mySeatId)
+	return aoc.Int(mySeatId), nil
 }
@@ -0,0 +1,84 @@
+package day05
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+	"github.com/tangledhelix/adventofcode2020/aoc/aoctest"
+)
+
+func TestSeatLocation(t *testing.T) {
+	grid := gridSpec{rows: 128, cols: 8, rowLetters: 7, colLetters: 3}
+
+	tests := []struct {
+		locator string
+		row     int
+		col     int
+		id      int
+	}{
+		{"FBFBBFFRLR", 44, 5, 357},
+		{"BFFFBBFRRR", 70, 7, 567},
+		{"FFFBBBFRRR", 14, 7, 119},
+		{"BBFFBBFRLL", 102, 4, 820},
+	}
+
+	for _, tt := range tests {
+		row, col := findSeatLocation(tt.locator, grid)
+		id := calculateSeatId(row, col, grid)
+		if row != tt.row || col != tt.col || id != tt.id {
+			t.Errorf("%s: row %d, column %d, seat ID %d; want row %d, column %d, seat ID %d",
+				tt.locator, row, col, id, tt.row, tt.col, tt.id)
+		}
+	}
+}
+
+func TestExamples(t *testing.T) {
+	aoctest.Run(t, solver{}, []aoctest.Case{
+		{
+			Name:  "boarding passes",
+			Input: "FBFBBFFRLR\nBFFFBBFRRR\nFFFBBBFRRR\nBBFFBBFRLL\n",
+			Part1: "820",
+		},
+	})
+}
+
+func TestReadSeatsErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		line, col int
+	}{
+		{"no column letters", "FBFBBFF\n", 1, 1},
+		{"bad row letter", "FBFBBFFRLR\nFBXBBFFRLR\n", 2, 3},
+		{"too short", "FBFBBFFRLR\nFBFBBFFRL\n", 2, 10},
+		{"63 row letters", strings.Repeat("F", 63) + "L\n", 1, maxSeatLetters + 1},
+		{"64 row letters", strings.Repeat("F", 64) + "L\n", 1, maxSeatLetters + 1},
+		{"too many in all", strings.Repeat("F", 15) + strings.Repeat("L", 15) + "\n", 1, maxSeatLetters + 1},
+	}
+
+	for _, tt := range tests {
+		_, _, _, err := readSeats(strings.NewReader(tt.text))
+		var pe *aoc.ParseError
+		if !errors.As(err, &pe) || pe.Line != tt.line || pe.Col != tt.col {
+			t.Errorf("%s: readSeats = %v, want an error at %d:%d", tt.name, err, tt.line, tt.col)
+		}
+	}
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, solver{}, "904", "669")
+}
+
+func BenchmarkParse(b *testing.B) {
+	aoctest.BenchmarkParse(b, solver{})
+}
+
+func BenchmarkPart1(b *testing.B) {
+	aoctest.BenchmarkPart(b, solver{}.Part1)
+}
+
+func BenchmarkPart2(b *testing.B) {
+	aoctest.BenchmarkPart(b, solver{}.Part2)
+}
@@ -98,30 +98,80 @@
  * "yes". What is the sum of those counts?
  */
 
-package main
+package day06
 
 import (
-	"fmt"
 	"io"
-	"os"
-	"strings"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+	"github.com/tangledhelix/adventofcode2020/input"
 )
 
-func main() {
-	// Read input file into an array of strings (one per line)
-	dat, err := os.ReadFile("input.txt")
-	check(err)
-	rawData := strings.Split(string(dat), "\n")
+func init() {
+	aoc.Register(2020, 6, solver{})
+}
+
+type solver struct{}
+
+func (solver) Parse(input io.Reader) error {
+	_, err := readGroups(input)
+	return err
+}
+
+func (solver) Part1(input io.Reader) (aoc.Answer, error) {
+	groups, err := readGroups(input)
+	if err != nil {
+		return "", err
+	}
+
+	total, _ := tallyAnswers(groups)
+	return aoc.Int(total), nil
+}
+
+func (solver) Part2(input io.Reader) (aoc.Answer, error) {
+	groups, err := readGroups(input)
+	if err != nil {
+		return "", err
+	}
 
+	_, total := tallyAnswers(groups)
+	return aoc.Int(total), nil
+}
+
+// Read the input into groups, each a list of one person's answers. Groups
+// are separated by blank lines.
+func readGroups(r io.Reader) ([][]string, error) {
+	blocks, err := input.Blocks(r)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([][]string, len(blocks))
+	for i, block := range blocks {
+		for j, line := range block.Lines {
+			for col, c := range line {
+				// Questions are only ever a through z.
+				if c < 'a' || c > 'z' {
+					return nil, aoc.Errorf(block.Line+j, col+1, line, "unknown question %q, want a-z", c)
+				}
+			}
+		}
+		groups[i] = block.Lines
+	}
+
+	return groups, nil
+}
+
+// Both rounds are counted in the same pass over the groups. Round 1 counts
+// the questions anyone in a group answered, round 2 the ones everyone
+// answered.
+func tallyAnswers(groups [][]string) (int, int) {
 	// Track the running total (this is a sum of the counts of the questions
 	// asked in each group). This is the total for round 1.
 	totalRound1Count := 0
 	// Now track a running total, the sum of counts of questions answered
 	// "yes" by *everyone* in a group. This is the total for round 2.
 	totalRound2Count := 0
-	// We also want to know how many answering parties exist in this group.
-	// That's how we know which were answered yes by everyone.
-	round2Answerers := 0
 
 	// On each turn we'll mark which questions have been answered, using this
 	// map. Between turns, reset the map so it's all false again.
@@ -142,42 +192,24 @@ func main() {
 		"q": 0, "r": 0, "s": 0, "t": 0, "u": 0, "v": 0, "w": 0, "x": 0,
 		"y": 0, "z": 0}
 
-	for i := 0; i < len(rawData); i++ {
-		line := rawData[i]
-
-		// Blank line means a new record has started. Count up this group's
-		// answers and add to the running total, then reset the data for next
-		// round.
-		if line == "" {
-			group1Count := countGroup1Responses(letters)
-			group2Count := countGroup2Responses(letters2, round2Answerers)
-			totalRound1Count += group1Count
-			totalRound2Count += group2Count
-			lettersReset(letters)
-			letters2Reset(letters2)
-			round2Answerers = 0
-			continue
-		}
-
-		round2Answerers++
-		for _, c := range line {
-			letters[string(c)] = true
-			letters2[string(c)]++
+	for _, group := range groups {
+		for _, line := range group {
+			for _, c := range line {
+				letters[string(c)] = true
+				letters2[string(c)]++
+			}
 		}
 
+		// Count up this group's answers and add to the running total, then
+		// reset the data for next round. Everyone in the group answered, so
+		// the group size tells us which were answered yes by everyone.
+		totalRound1Count += countGroup1Responses(letters)
+		totalRound2Count += countGroup2Responses(letters2, len(group))
+		lettersReset(letters)
+		letters2Reset(letters2)
 	}
 
-	fmt.Printf("Total in round 1: %d\n", totalRound1Count)
-	fmt.Printf("Total in round 2: %d\n", totalRound2Count)
-
-}
-
-func check(e error) {
-	if e != nil {
-		if e != io.EOF {
-			panic(e)
-		}
-	}
+	return totalRound1Count, totalRound2Count
 }
 
 // Reset all letters to false (do this between groups)
@@ -0,0 +1,41 @@
+package day06
+
+import (
+	"testing"
+
+	"github.com/tangledhelix/adventofcode2020/aoc/aoctest"
+)
+
+func TestExamples(t *testing.T) {
+	aoctest.Run(t, solver{}, []aoctest.Case{
+		{Name: "one group", Input: "abcx\nabcy\nabcz\n", Part1: "6", Part2: "3"},
+		{
+			Name:  "five groups",
+			Input: "abc\n\na\nb\nc\n\nab\nac\n\na\na\na\na\n\nb\n",
+			Part1: "11",
+			Part2: "6",
+		},
+		{
+			Name:  "CRLF without final newline",
+			Input: "abc\r\n\r\na\r\nb\r\nc\r\n\r\nab\r\nac\r\n\r\na\r\na\r\na\r\na\r\n\r\nb",
+			Part1: "11",
+			Part2: "6",
+		},
+	})
+}
+
+func TestGolden(t *testing.T) {
+	aoctest.Golden(t, solver{}, "6726", "3316")
+}
+
+func BenchmarkParse(b *testing.B) {
+	aoctest.BenchmarkParse(b, solver{})
+}
+
+func BenchmarkPart1(b *testing.B) {
+	aoctest.BenchmarkPart(b, solver{}.Part1)
+}
+
+func BenchmarkPart2(b *testing.B) {
+	aoctest.BenchmarkPart(b, solver{}.Part2)
+}
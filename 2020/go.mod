@@ -1,6 +1,6 @@
 module github.com/tangledhelix/adventofcode2020
 
-go 1.17
+go 1.18
 
 require (
 	github.com/soroushj/menge v1.1.2 // indirect
@@ -0,0 +1,201 @@
+// Package grid holds rectangular maps of squares, like the ones many puzzles
+// are drawn on, and knows what happens at their edges: some maps repeat
+// forever in one or both directions, some stop dead, and some stretch their
+// edge squares outwards.
+package grid
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+	"github.com/tangledhelix/adventofcode2020/input"
+)
+
+// A Point is a square's column X and row Y, counting from 0 at the top left.
+// Rows go down the page.
+type Point struct {
+	X, Y int
+}
+
+// Add returns p moved by q.
+func (p Point) Add(q Point) Point {
+	return Point{p.X + q.X, p.Y + q.Y}
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("(%d,%d)", p.X, p.Y)
+}
+
+// The directions to the four squares sharing a side with a square, and to
+// all eight squares around it.
+var (
+	Orthogonal = []Point{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+	Adjacent   = []Point{{-1, -1}, {0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}}
+)
+
+// Edge says what lies past the edges of a grid.
+type Edge int
+
+const (
+	// Bounded grids stop at their edges; there's nothing past them.
+	Bounded Edge = iota
+	// WrapX grids repeat forever to the left and right, but stop at the top
+	// and bottom.
+	WrapX
+	// WrapBoth grids repeat forever in every direction.
+	WrapBoth
+	// Clamp grids carry on past their edges with copies of the nearest edge
+	// square.
+	Clamp
+)
+
+// A Grid is a rectangle of squares holding a T each.
+type Grid[T any] struct {
+	width, height int
+	edge          Edge
+	squares       []T // row by row
+}
+
+// New returns a grid of the given size with every square holding the zero T.
+func New[T any](width, height int, edge Edge) *Grid[T] {
+	if width < 0 || height < 0 {
+		panic(fmt.Sprintf("grid: negative size %dx%d", width, height))
+	}
+	return &Grid[T]{width: width, height: height, edge: edge, squares: make([]T, width*height)}
+}
+
+// Parse reads a map drawn with one character per square, one row per line,
+// converting each character with square. Rows must all be the same width.
+// Errors from square are returned as *aoc.ParseErrors pointing at the
+// character.
+func Parse[T any](r io.Reader, edge Edge, square func(c byte) (T, error)) (*Grid[T], error) {
+	rows, err := input.Grid(r)
+	if err != nil {
+		return nil, err
+	}
+
+	width := 0
+	if len(rows) > 0 {
+		width = len(rows[0])
+	}
+	g := New[T](width, len(rows), edge)
+	for y, row := range rows {
+		for x, c := range row {
+			v, err := square(c)
+			if err != nil {
+				return nil, aoc.Errorf(y+1, x+1, string(row), "%v", err)
+			}
+			g.squares[y*width+x] = v
+		}
+	}
+
+	return g, nil
+}
+
+// Width returns the number of columns in one copy of the grid.
+func (g *Grid[T]) Width() int {
+	return g.width
+}
+
+// Height returns the number of rows in one copy of the grid.
+func (g *Grid[T]) Height() int {
+	return g.height
+}
+
+// Edge returns what lies past the grid's edges.
+func (g *Grid[T]) Edge() Edge {
+	return g.edge
+}
+
+// Resolve returns the square of the grid itself that p lands on, following
+// the grid's edges, or false if p is off the grid.
+func (g *Grid[T]) Resolve(p Point) (Point, bool) {
+	if g.width == 0 || g.height == 0 {
+		return p, false
+	}
+
+	switch g.edge {
+	case WrapX:
+		p.X = wrap(p.X, g.width)
+	case WrapBoth:
+		p.X = wrap(p.X, g.width)
+		p.Y = wrap(p.Y, g.height)
+	case Clamp:
+		p.X = clamp(p.X, g.width)
+		p.Y = clamp(p.Y, g.height)
+	}
+
+	return p, p.X >= 0 && p.X < g.width && p.Y >= 0 && p.Y < g.height
+}
+
+func wrap(n, size int) int {
+	n %= size
+	if n < 0 {
+		n += size
+	}
+	return n
+}
+
+func clamp(n, size int) int {
+	if n < 0 {
+		return 0
+	}
+	if n >= size {
+		return size - 1
+	}
+	return n
+}
+
+// Contains reports whether p is on the grid, following its edges.
+func (g *Grid[T]) Contains(p Point) bool {
+	_, ok := g.Resolve(p)
+	return ok
+}
+
+// At returns what's in the square at p, or false if p is off the grid.
+func (g *Grid[T]) At(p Point) (T, bool) {
+	p, ok := g.Resolve(p)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return g.squares[p.Y*g.width+p.X], true
+}
+
+// Get returns what's in the square at p, or the zero T if p is off the grid.
+func (g *Grid[T]) Get(p Point) T {
+	v, _ := g.At(p)
+	return v
+}
+
+// Set puts v in the square at p, which on a wrapping or clamped grid is the
+// same square as all its copies. It reports false, changing nothing, if p is
+// off the grid.
+func (g *Grid[T]) Set(p Point, v T) bool {
+	p, ok := g.Resolve(p)
+	if ok {
+		g.squares[p.Y*g.width+p.X] = v
+	}
+	return ok
+}
+
+// Each calls fn for every square of the grid itself, row by row.
+func (g *Grid[T]) Each(fn func(p Point, v T)) {
+	for i, v := range g.squares {
+		fn(Point{i % g.width, i / g.width}, v)
+	}
+}
+
+// Neighbors calls fn for each square one step from p in the given directions,
+// usually Orthogonal or Adjacent, skipping those off the grid. The points
+// passed to fn are where the steps lead, not where they resolve to, so on a
+// wrapping grid they may lie outside the grid itself.
+func (g *Grid[T]) Neighbors(p Point, dirs []Point, fn func(p Point, v T)) {
+	for _, d := range dirs {
+		q := p.Add(d)
+		if v, ok := g.At(q); ok {
+			fn(q, v)
+		}
+	}
+}
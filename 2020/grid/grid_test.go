@@ -0,0 +1,138 @@
+package grid
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+)
+
+// digits parses a map of digits.
+func digits(t *testing.T, text string, edge Edge) *Grid[int] {
+	t.Helper()
+
+	g, err := Parse(strings.NewReader(text), edge, func(c byte) (int, error) {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("not a digit")
+		}
+		return int(c - '0'), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestAt(t *testing.T) {
+	const text = "123\n456\n"
+
+	tests := []struct {
+		edge Edge
+		p    Point
+		want int
+		ok   bool
+	}{
+		{Bounded, Point{0, 0}, 1, true},
+		{Bounded, Point{2, 1}, 6, true},
+		{Bounded, Point{3, 0}, 0, false},
+		{Bounded, Point{-1, 0}, 0, false},
+		{Bounded, Point{0, 2}, 0, false},
+
+		{WrapX, Point{3, 0}, 1, true},
+		{WrapX, Point{7, 1}, 5, true},
+		{WrapX, Point{-1, 0}, 3, true},
+		{WrapX, Point{-7, 1}, 6, true},
+		{WrapX, Point{0, 2}, 0, false},
+		{WrapX, Point{0, -1}, 0, false},
+
+		{WrapBoth, Point{3, 2}, 1, true},
+		{WrapBoth, Point{-1, -1}, 6, true},
+		{WrapBoth, Point{100, 101}, 5, true},
+
+		{Clamp, Point{-5, -5}, 1, true},
+		{Clamp, Point{5, 0}, 3, true},
+		{Clamp, Point{1, 9}, 5, true},
+	}
+
+	for _, tt := range tests {
+		g := digits(t, text, tt.edge)
+		got, ok := g.At(tt.p)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("edge %d: At(%v) = %d, %v, want %d, %v", tt.edge, tt.p, got, ok, tt.want, tt.ok)
+		}
+		if g.Contains(tt.p) != tt.ok {
+			t.Errorf("edge %d: Contains(%v) = %v, want %v", tt.edge, tt.p, !tt.ok, tt.ok)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	g := New[string](2, 2, WrapBoth)
+	if !g.Set(Point{3, -1}, "x") {
+		t.Fatal("Set on a wrapping grid failed")
+	}
+	if got := g.Get(Point{1, 1}); got != "x" {
+		t.Errorf("Get(1,1) = %q, want the x set at (3,-1)", got)
+	}
+
+	b := New[string](2, 2, Bounded)
+	if b.Set(Point{2, 0}, "x") {
+		t.Error("Set off a bounded grid succeeded")
+	}
+}
+
+func TestEachAndNeighbors(t *testing.T) {
+	g := digits(t, "123\n456\n789\n", Bounded)
+
+	sum := 0
+	g.Each(func(p Point, v int) { sum += v })
+	if sum != 45 {
+		t.Errorf("Each visited squares summing to %d, want 45", sum)
+	}
+
+	var got []int
+	g.Neighbors(Point{0, 0}, Adjacent, func(p Point, v int) { got = append(got, v) })
+	if want := []int{2, 5, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Adjacent neighbors of the corner = %v, want %v", got, want)
+	}
+
+	got = nil
+	g.Neighbors(Point{1, 1}, Orthogonal, func(p Point, v int) { got = append(got, v) })
+	if want := []int{2, 6, 8, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Orthogonal neighbors of the middle = %v, want %v", got, want)
+	}
+
+	var points []Point
+	w := digits(t, "12\n34\n", WrapX)
+	w.Neighbors(Point{0, 0}, Orthogonal, func(p Point, v int) { points = append(points, p) })
+	if want := []Point{{1, 0}, {0, 1}, {-1, 0}}; !reflect.DeepEqual(points, want) {
+		t.Errorf("Orthogonal neighbors of a wrapped corner = %v, want %v", points, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	square := func(c byte) (int, error) {
+		if c == '?' {
+			return 0, errors.New("unreadable square")
+		}
+		return 0, nil
+	}
+
+	tests := []struct {
+		text      string
+		line, col int
+	}{
+		{"...\n.?.\n", 2, 2},
+		{"...\n....\n", 2, 1},
+	}
+	for _, tt := range tests {
+		_, err := Parse(strings.NewReader(tt.text), Bounded, square)
+		var pe *aoc.ParseError
+		if !errors.As(err, &pe) || pe.Line != tt.line || pe.Col != tt.col {
+			t.Errorf("Parse(%q) = %v, want an error at %d:%d", tt.text, err, tt.line, tt.col)
+		}
+	}
+}
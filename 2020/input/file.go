@@ -0,0 +1,48 @@
+package input
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+)
+
+// Open opens the named file, or standard input if the name is empty or "-",
+// the way commands that take an input file do. Closing standard input this
+// way leaves it open.
+func Open(name string) (io.ReadCloser, error) {
+	if IsStdin(name) {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(name)
+}
+
+// IsStdin reports whether Open takes the name to mean standard input.
+func IsStdin(name string) bool {
+	return name == "" || name == "-"
+}
+
+// Named adds the name of the file that was read to a parse error in err, if
+// the error doesn't already name one. Standard input isn't named.
+func Named(err error, name string) error {
+	var pe *aoc.ParseError
+	if errors.As(err, &pe) && pe.File == "" && !IsStdin(name) {
+		pe.File = name
+	}
+	return err
+}
+
+// ReadFile opens the named file with Open, reads it with read, and names the
+// file in any parse error.
+func ReadFile[T any](name string, read func(io.Reader) (T, error)) (T, error) {
+	f, err := Open(name)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	defer f.Close()
+
+	v, err := read(f)
+	return v, Named(err, name)
+}
@@ -0,0 +1,114 @@
+// Package input reads puzzle inputs in the shapes they usually come in:
+// lines, blocks of lines separated by blank lines, lists of integers and
+// grids of characters. It also opens the input files commands are given,
+// with "-" or no name at all meaning standard input.
+//
+// All of them treat line endings the same way. "\r\n" is the same as "\n",
+// and a line ending at the very end of the input doesn't start another,
+// empty, line. So an input reads the same whether or not it ends with a
+// newline, and whichever system it was saved on.
+package input
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+)
+
+// Lines reads the whole input and splits it into lines, without their line
+// endings.
+func Lines(r io.Reader) ([]string, error) {
+	dat, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return SplitLines(string(dat)), nil
+}
+
+// SplitLines splits text into lines the same way Lines does.
+func SplitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	lines := strings.Split(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
+// A Block is a group of consecutive non-blank lines.
+type Block struct {
+	Line  int      // 1-based line number of the first line in the block
+	Lines []string // the lines, without line endings
+}
+
+// Blocks reads the whole input and splits it into blocks separated by blank
+// lines. Runs of several blank lines, and blank lines at the start or end of
+// the input, don't make empty blocks.
+func Blocks(r io.Reader) ([]Block, error) {
+	lines, err := Lines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var blocks []Block
+	var block *Block
+	for i, line := range lines {
+		if line == "" {
+			block = nil
+			continue
+		}
+		if block == nil {
+			blocks = append(blocks, Block{Line: i + 1})
+			block = &blocks[len(blocks)-1]
+		}
+		block.Lines = append(block.Lines, line)
+	}
+
+	return blocks, nil
+}
+
+// Ints reads the whole input as one integer per line.
+func Ints(r io.Reader) ([]int, error) {
+	lines, err := Lines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	nums := make([]int, len(lines))
+	for i, line := range lines {
+		n, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, aoc.Errorf(i+1, 1, line, "not an integer")
+		}
+		nums[i] = n
+	}
+
+	return nums, nil
+}
+
+// Grid reads the whole input as a rectangle of bytes, one row per line. All
+// rows must be the same width.
+func Grid(r io.Reader) ([][]byte, error) {
+	lines, err := Lines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	grid := make([][]byte, len(lines))
+	for i, line := range lines {
+		if len(line) != len(lines[0]) {
+			return nil, aoc.Errorf(i+1, 1, line, "row is %d wide, want %d like the first row", len(line), len(lines[0]))
+		}
+		grid[i] = []byte(line)
+	}
+
+	return grid, nil
+}
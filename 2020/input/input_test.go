@@ -0,0 +1,133 @@
+package input
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"\n", []string{""}},
+		{"a", []string{"a"}},
+		{"a\n", []string{"a"}},
+		{"a\nb", []string{"a", "b"}},
+		{"a\nb\n", []string{"a", "b"}},
+		{"a\r\nb\r\n", []string{"a", "b"}},
+		{"a\n\nb\n\n", []string{"a", "", "b", ""}},
+	}
+
+	for _, tt := range tests {
+		got, err := Lines(strings.NewReader(tt.in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Lines(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	want := []Block{
+		{Line: 1, Lines: []string{"abc"}},
+		{Line: 3, Lines: []string{"a", "b"}},
+		{Line: 7, Lines: []string{"c"}},
+	}
+
+	for _, in := range []string{
+		"abc\n\na\nb\n\n\nc",
+		"abc\n\na\nb\n\n\nc\n",
+		"abc\n\na\nb\n\n\nc\n\n",
+		"abc\r\n\r\na\r\nb\r\n\r\n\r\nc\r\n",
+	} {
+		got, err := Blocks(strings.NewReader(in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Blocks(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestInts(t *testing.T) {
+	got, err := Ints(strings.NewReader("1721\r\n-979\n366\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1721, -979, 366}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Ints = %v, want %v", got, want)
+	}
+
+	_, err = Ints(strings.NewReader("1\n\n2\n"))
+	var pe *aoc.ParseError
+	if !errors.As(err, &pe) || pe.Line != 2 {
+		t.Errorf("Ints with a blank line: got %v, want a parse error on line 2", err)
+	}
+}
+
+func TestGrid(t *testing.T) {
+	got, err := Grid(strings.NewReader("..#\r\n#..\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]byte{[]byte("..#"), []byte("#..")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Grid = %q, want %q", got, want)
+	}
+
+	_, err = Grid(strings.NewReader("..#\n#.\n"))
+	var pe *aoc.ParseError
+	if !errors.As(err, &pe) || pe.Line != 2 {
+		t.Errorf("ragged Grid: got %v, want a parse error on line 2", err)
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "nums.txt")
+	if err := os.WriteFile(name, []byte("1\n2\nthree\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := ReadFile(name, Ints)
+	var pe *aoc.ParseError
+	if !errors.As(err, &pe) || pe.File != name || pe.Line != 3 {
+		t.Errorf("ReadFile = %v, want an error in %s on line 3", err, name)
+	}
+
+	if _, err := ReadFile(filepath.Join(t.TempDir(), "missing"), Ints); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ReadFile of a missing file = %v, want it not to exist", err)
+	}
+}
+
+func TestNamed(t *testing.T) {
+	tests := []struct {
+		name, file string
+		want       string
+	}{
+		{"in.txt", "", "in.txt"},
+		{"-", "", ""},
+		{"", "", ""},
+		{"in.txt", "slopes.txt", "slopes.txt"},
+	}
+	for _, tt := range tests {
+		pe := &aoc.ParseError{File: tt.file, Line: 1, Col: 1, Err: errors.New("bad")}
+		Named(pe, tt.name)
+		if pe.File != tt.want {
+			t.Errorf("Named(error in %q, %q) names %q, want %q", tt.file, tt.name, pe.File, tt.want)
+		}
+	}
+
+	if err := Named(nil, "in.txt"); err != nil {
+		t.Errorf("Named(nil) = %v, want nil", err)
+	}
+}
@@ -0,0 +1,172 @@
+// Package inputs finds puzzle inputs. Everyone gets a different input for the
+// same puzzle, so inputs are kept in a cache directory keyed by year, day and
+// user, laid out like this:
+//
+//	<cache>/2020/day06/alice.txt
+//	<cache>/2020/day06/bob.txt
+//
+// The input.txt files checked in next to each day's code are used when no
+// user has been picked.
+package inputs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultUser is the user name used when nobody has been picked. Their inputs
+// come from the cache if they've been put there, and from the checked in
+// input.txt files otherwise.
+const DefaultUser = "default"
+
+// A Provider resolves the input file for a puzzle.
+type Provider struct {
+	CacheDir string // root of the input cache
+	TreeDir  string // directory holding the dayNN folders with input.txt
+	User     string // whose inputs to use; "" means DefaultUser
+}
+
+// NewProvider returns a Provider configured from the environment: the cache
+// directory comes from $AOC_CACHE_DIR and the user from $AOC_USER, with
+// DefaultCacheDir and DefaultUser used when they're not set.
+func NewProvider(treeDir string) (*Provider, error) {
+	cacheDir := os.Getenv("AOC_CACHE_DIR")
+	if cacheDir == "" {
+		var err error
+		cacheDir, err = DefaultCacheDir()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Provider{
+		CacheDir: cacheDir,
+		TreeDir:  treeDir,
+		User:     os.Getenv("AOC_USER"),
+	}, nil
+}
+
+// DefaultCacheDir returns the cache directory used when none is configured,
+// under the user's standard cache directory.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "adventofcode", "inputs"), nil
+}
+
+// UserName returns whose inputs the Provider is using.
+func (p *Provider) UserName() string {
+	if p.User == "" {
+		return DefaultUser
+	}
+	return p.User
+}
+
+// CachePath returns where the input for a puzzle lives in the cache, whether
+// or not it's there yet.
+func (p *Provider) CachePath(year, day int) (string, error) {
+	user := p.UserName()
+	if err := checkUser(user); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(p.CacheDir, fmt.Sprint(year), fmt.Sprintf("day%02d", day), user+".txt"), nil
+}
+
+// TreePath returns the path of the input.txt checked in for a day.
+func (p *Provider) TreePath(day int) string {
+	return filepath.Join(p.TreeDir, fmt.Sprintf("day%02d", day), "input.txt")
+}
+
+// Path returns the input file to use for a puzzle. The cache wins if it has
+// the input. Otherwise the default user falls back to the checked in
+// input.txt, but anyone else gets an error, because that file belongs to
+// somebody else and would give them the wrong answers.
+func (p *Provider) Path(year, day int) (string, error) {
+	cached, err := p.CachePath(year, day)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(cached); err == nil {
+		return cached, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	if p.UserName() != DefaultUser {
+		return "", fmt.Errorf("no %d day %d input for user %q (looked for %s)", year, day, p.UserName(), cached)
+	}
+
+	tree := p.TreePath(day)
+	if _, err := os.Stat(tree); err != nil {
+		return "", fmt.Errorf("no %d day %d input in %s or %s", year, day, cached, tree)
+	}
+	return tree, nil
+}
+
+// Read returns the input for a puzzle along with the path it was read from.
+func (p *Provider) Read(year, day int) ([]byte, string, error) {
+	path, err := p.Path(year, day)
+	if err != nil {
+		return nil, "", err
+	}
+
+	dat, err := os.ReadFile(path)
+	return dat, path, err
+}
+
+// Cached reports whether the cache has the input for a puzzle.
+func (p *Provider) Cached(year, day int) (bool, error) {
+	path, err := p.CachePath(year, day)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Store saves the input for a puzzle in the cache. The file is written under
+// a temporary name and renamed into place, so an interrupted write can't
+// leave a truncated input behind to be mistaken for the real thing.
+func (p *Provider) Store(year, day int, dat []byte) error {
+	path, err := p.CachePath(year, day)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".input-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(dat); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// User names become file names, so keep them to something that can't climb
+// out of the cache directory.
+func checkUser(user string) error {
+	if user == "." || user == ".." || strings.ContainsAny(user, `/\`) || strings.ContainsRune(user, filepath.Separator) {
+		return fmt.Errorf("invalid user name %q", user)
+	}
+	return nil
+}
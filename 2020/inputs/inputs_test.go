@@ -0,0 +1,74 @@
+package inputs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPath(t *testing.T) {
+	cache := t.TempDir()
+	tree := t.TempDir()
+
+	writeFile(t, filepath.Join(tree, "day01", "input.txt"), "tree\n")
+	writeFile(t, filepath.Join(cache, "2020", "day01", "alice.txt"), "alice\n")
+	writeFile(t, filepath.Join(cache, "2020", "day02", "default.txt"), "default\n")
+	writeFile(t, filepath.Join(tree, "day02", "input.txt"), "tree\n")
+
+	tests := []struct {
+		user    string
+		day     int
+		want    string
+		wantErr bool
+	}{
+		{user: "", day: 1, want: filepath.Join(tree, "day01", "input.txt")},
+		{user: "alice", day: 1, want: filepath.Join(cache, "2020", "day01", "alice.txt")},
+		{user: "bob", day: 1, wantErr: true},
+		{user: "", day: 2, want: filepath.Join(cache, "2020", "day02", "default.txt")},
+		{user: "alice", day: 2, wantErr: true},
+		{user: "", day: 3, wantErr: true},
+		{user: "../alice", day: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		p := &Provider{CacheDir: cache, TreeDir: tree, User: tt.user}
+		got, err := p.Path(2020, tt.day)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("user %q day %d: got %s, want error", tt.user, tt.day, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("user %q day %d: %v", tt.user, tt.day, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("user %q day %d: got %s, want %s", tt.user, tt.day, got, tt.want)
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	cache := t.TempDir()
+	writeFile(t, filepath.Join(cache, "2020", "day06", "bob.txt"), "abc\n")
+
+	p := &Provider{CacheDir: cache, TreeDir: t.TempDir(), User: "bob"}
+	dat, path, err := p.Read(2020, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(dat) != "abc\n" || path != filepath.Join(cache, "2020", "day06", "bob.txt") {
+		t.Errorf("Read = %q from %s", dat, path)
+	}
+}
@@ -0,0 +1,164 @@
+// Package ledger keeps a record of every answer submitted to the site, so we
+// never send an answer we already know is wrong. Each attempt is one line of
+// JSON appended to the ledger file, which makes the file safe to read, grep
+// and merge by hand.
+package ledger
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+	"github.com/tangledhelix/adventofcode2020/client"
+)
+
+// An Entry records one submitted answer and what the site made of it.
+type Entry struct {
+	Time    time.Time      `json:"time"`
+	User    string         `json:"user"`
+	Year    int            `json:"year"`
+	Day     int            `json:"day"`
+	Part    int            `json:"part"`
+	Answer  aoc.Answer     `json:"answer"`
+	Outcome client.Outcome `json:"outcome"`
+	Wait    time.Duration  `json:"wait,omitempty"`
+	Message string         `json:"message,omitempty"`
+}
+
+// A Ledger holds the entries read from a ledger file, and adds new ones to
+// the end of it.
+type Ledger struct {
+	path    string
+	entries []Entry
+}
+
+// DefaultPath returns the ledger file used when none is configured, next to
+// the input cache under the user's standard cache directory.
+func DefaultPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "adventofcode", "answers.jsonl"), nil
+}
+
+// Open reads the ledger at path. A ledger that doesn't exist yet is empty.
+func Open(path string) (*Ledger, error) {
+	l := &Ledger{path: path}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, &aoc.ParseError{File: path, Line: lineNum, Text: scanner.Text(), Err: err}
+		}
+		l.entries = append(l.entries, e)
+	}
+
+	return l, scanner.Err()
+}
+
+// Entries returns the attempts recorded for one part of a puzzle by one user,
+// oldest first.
+func (l *Ledger) Entries(user string, year, day, part int) []Entry {
+	var found []Entry
+	for _, e := range l.entries {
+		if e.User == user && e.Year == year && e.Day == day && e.Part == part {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// A Refusal explains why an answer shouldn't be submitted.
+type Refusal struct {
+	Answer aoc.Answer
+	Reason string
+}
+
+func (r *Refusal) Error() string {
+	return fmt.Sprintf("not submitting %s: %s", r.Answer, r.Reason)
+}
+
+// Check looks through earlier attempts to see whether submitting answer could
+// possibly be worthwhile. It returns a *Refusal if the part is already
+// solved, if the same answer was already rejected, if an earlier "too high"
+// or "too low" rules it out, or if the site told us to wait and that time
+// isn't up yet.
+func (l *Ledger) Check(user string, year, day, part int, answer aoc.Answer, now time.Time) error {
+	refuse := func(format string, a ...interface{}) error {
+		return &Refusal{Answer: answer, Reason: fmt.Sprintf(format, a...)}
+	}
+
+	n, numErr := answer.Int()
+
+	for _, e := range l.Entries(user, year, day, part) {
+		switch {
+		case e.Outcome == client.Correct && e.Answer == answer:
+			return refuse("already accepted on %s", e.Time.Format(time.RFC1123))
+		case e.Outcome == client.Correct:
+			return refuse("part already solved, with %s", e.Answer)
+		case e.Outcome.Wrong() && e.Answer == answer:
+			return refuse("already rejected (%s) on %s", e.Outcome, e.Time.Format(time.RFC1123))
+		case e.Outcome == client.Wait && now.Before(e.Time.Add(e.Wait)):
+			return refuse("site asked us to wait until %s", e.Time.Add(e.Wait).Format(time.Kitchen))
+		}
+
+		// Bounds only mean something when both answers are numbers.
+		bound, err := e.Answer.Int()
+		if numErr != nil || err != nil {
+			continue
+		}
+		if e.Outcome == client.TooHigh && n >= bound {
+			return refuse("%s was already too high", e.Answer)
+		}
+		if e.Outcome == client.TooLow && n <= bound {
+			return refuse("%s was already too low", e.Answer)
+		}
+	}
+
+	return nil
+}
+
+// Record adds an entry to the ledger, appending it to the ledger file.
+func (l *Ledger) Record(e Entry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	l.entries = append(l.entries, e)
+	return nil
+}
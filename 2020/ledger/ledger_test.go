@@ -0,0 +1,87 @@
+package ledger
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tangledhelix/adventofcode2020/aoc"
+	"github.com/tangledhelix/adventofcode2020/client"
+)
+
+var start = time.Date(2020, 12, 5, 5, 0, 0, 0, time.UTC)
+
+func TestCheck(t *testing.T) {
+	l, err := Open(filepath.Join(t.TempDir(), "answers.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attempts := []Entry{
+		{User: "alice", Day: 5, Part: 1, Answer: "850", Outcome: client.TooLow},
+		{User: "alice", Day: 5, Part: 1, Answer: "950", Outcome: client.TooHigh},
+		{User: "alice", Day: 5, Part: 1, Answer: "abc", Outcome: client.Incorrect},
+		{User: "alice", Day: 5, Part: 2, Answer: "669", Outcome: client.Correct},
+		{User: "alice", Day: 6, Part: 1, Answer: "1", Outcome: client.Wait, Wait: time.Minute},
+	}
+	for _, e := range attempts {
+		e.Time = start
+		e.Year = 2020
+		if err := l.Record(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		user    string
+		day     int
+		part    int
+		answer  aoc.Answer
+		now     time.Time
+		refused bool
+	}{
+		{"alice", 5, 1, "904", start, false},
+		{"alice", 5, 1, "850", start, true},
+		{"alice", 5, 1, "800", start, true},
+		{"alice", 5, 1, "950", start, true},
+		{"alice", 5, 1, "1000", start, true},
+		{"alice", 5, 1, "abc", start, true},
+		{"alice", 5, 1, "xyz", start, false},
+		{"alice", 5, 2, "669", start, true},
+		{"alice", 5, 2, "670", start, true},
+		{"alice", 6, 1, "2", start.Add(30 * time.Second), true},
+		{"alice", 6, 1, "2", start.Add(2 * time.Minute), false},
+		{"bob", 5, 1, "850", start, false},
+	}
+
+	for _, tt := range tests {
+		err := l.Check(tt.user, 2020, tt.day, tt.part, tt.answer, tt.now)
+		var r *Refusal
+		if refused := errors.As(err, &r); refused != tt.refused {
+			t.Errorf("%s day %d part %d answer %s: got %v, refused %t", tt.user, tt.day, tt.part, tt.answer, err, tt.refused)
+		}
+	}
+}
+
+func TestRecordAndOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "answers.jsonl")
+
+	l, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Entry{Time: start, User: "bob", Year: 2020, Day: 6, Part: 2, Answer: "3316", Outcome: client.Correct}
+	if err := l.Record(want); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := l.Entries("bob", 2020, 6, 2)
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("Entries = %+v, want [%+v]", got, want)
+	}
+}